@@ -0,0 +1,92 @@
+package terminal_go
+
+import "io"
+
+// is7BitC1 reports whether ESC b is the 7-bit form of a C1 control
+func is7BitC1(b byte) bool {
+	return b >= 0x40 && b <= 0x5F
+}
+
+// To8BitControls converts the 7-bit C1 controls in s (ESC [, ESC ], ESC P,
+// ESC \ and the other ESC Fe forms) to single 8-bit C1 bytes (0x9B, 0x9D,
+// 0x90, 0x9C, ...). Use it with terminals switched to 8-bit mode with
+// Select8BitControls. The result is not valid UTF-8, so the text around the
+// sequences should be limited to ASCII or the terminal's 8-bit character set.
+func To8BitControls(s string) string {
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1B && i+1 < len(s) && is7BitC1(s[i+1]) {
+			buf = append(buf, s[i+1]+0x40)
+			i++
+			continue
+		}
+		buf = append(buf, s[i])
+	}
+	return string(buf)
+}
+
+// To7BitControls converts 8-bit C1 control bytes (0x80-0x9F) in s to their
+// 7-bit ESC forms. s must be an 8-bit stream; in UTF-8 text these bytes are
+// part of multi-byte characters.
+func To7BitControls(s string) string {
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b >= 0x80 && b <= 0x9F {
+			buf = append(buf, 0x1B, b-0x40)
+			continue
+		}
+		buf = append(buf, s[i])
+	}
+	return string(buf)
+}
+
+// C1Writer is an io.Writer that emits 8-bit C1 controls: everything written
+// to it is converted with To8BitControls before being passed on. An ESC at
+// the end of one write is held until the next; call Flush when done.
+type C1Writer struct {
+	w          io.Writer
+	pendingESC bool
+	buf        []byte
+}
+
+// NewC1Writer creates a C1Writer that writes to w
+func NewC1Writer(w io.Writer) *C1Writer {
+	return &C1Writer{w: w}
+}
+
+// Write converts p and writes it to the underlying writer.
+// It reports len(p) bytes written unless the underlying writer fails
+func (c *C1Writer) Write(p []byte) (int, error) {
+	c.buf = c.buf[:0]
+	for _, b := range p {
+		if c.pendingESC {
+			c.pendingESC = false
+			if is7BitC1(b) {
+				c.buf = append(c.buf, b+0x40)
+				continue
+			}
+			c.buf = append(c.buf, 0x1B)
+		}
+		if b == 0x1B {
+			c.pendingESC = true
+			continue
+		}
+		c.buf = append(c.buf, b)
+	}
+	if len(c.buf) > 0 {
+		if _, err := c.w.Write(c.buf); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes an ESC held back from the last write
+func (c *C1Writer) Flush() error {
+	if !c.pendingESC {
+		return nil
+	}
+	c.pendingESC = false
+	_, err := c.w.Write([]byte{0x1B})
+	return err
+}
@@ -0,0 +1,124 @@
+package terminal_go
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestTo8BitControls verifies conversion between 7-bit and 8-bit C1 controls
+func TestTo8BitControls(t *testing.T) {
+	tests := []struct {
+		seven, eight string
+	}{
+		{CursorPosition(5, 10), "\x9b5;10H"},
+		{"\033]0;title\033\\", "\x9d0;title\x9c"},
+		{"\033P1$r0m\033\\", "\x901$r0m\x9c"},
+		{ReverseIndex, "\x8d"},
+		{TabSet, "\x88"},
+		{SaveCursorPointerInMemory, SaveCursorPointerInMemory},
+		{DesignateCharacterSet(0, 'B'), DesignateCharacterSet(0, 'B')},
+		{"plain", "plain"},
+	}
+
+	for _, tt := range tests {
+		if got := To8BitControls(tt.seven); got != tt.eight {
+			t.Errorf("To8BitControls(%q) = %q, want %q", tt.seven, got, tt.eight)
+		}
+		if got := To7BitControls(tt.eight); got != tt.seven {
+			t.Errorf("To7BitControls(%q) = %q, want %q", tt.eight, got, tt.seven)
+		}
+	}
+}
+
+// TestC1Writer verifies that C1Writer converts introducers split across writes
+func TestC1Writer(t *testing.T) {
+	input := SetTextColor(1) + "x" + ReverseIndex + "\033]2;t\033\\" + "\033"
+	want := To8BitControls(input)
+
+	for size := 1; size <= len(input); size++ {
+		var buf bytes.Buffer
+		w := NewC1Writer(&buf)
+		for i := 0; i < len(input); i += size {
+			chunk := input[i:min(i+size, len(input))]
+			if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+				t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush() = %v", err)
+		}
+		if buf.String() != want {
+			t.Errorf("chunk size %d: got %q, want %q", size, buf.String(), want)
+		}
+	}
+}
+
+// TestParserEightBit verifies that an 8-bit parser recognizes C1 controls
+// and produces the same sequences as for the 7-bit forms
+func TestParserEightBit(t *testing.T) {
+	inputs := []string{
+		CursorPosition(5, 10),
+		EnterAltScreen,
+		"\033]0;title\033\\",
+		"\033P1$r0m\033\\",
+		"\033_Gi=1\033\\",
+	}
+
+	for _, input := range inputs {
+		eight := To8BitControls(input)
+		p := &Parser{EightBit: true}
+		got := p.Parse([]byte(eight))
+		want := ParseString(input)
+		for i := range want {
+			want[i].Raw = ""
+		}
+		for i := range got {
+			got[i].Raw = ""
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("8-bit Parse(%q) = %+v, want %+v", eight, got, want)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Action
+	}{
+		{"Latin-1 text", "caf\xe9", []Action{
+			{Type: ActionPrint, Rune: 'c', Raw: "c"},
+			{Type: ActionPrint, Rune: 'a', Raw: "a"},
+			{Type: ActionPrint, Rune: 'f', Raw: "f"},
+			{Type: ActionPrint, Rune: 'é', Raw: "\xe9"},
+		}},
+		{"C1 control", "\x85", []Action{
+			{Type: ActionExecute, Rune: 0x85, Raw: "\x85"},
+		}},
+		{"C1 control aborts sequence", "\x9b1\x84", []Action{
+			{Type: ActionExecute, Rune: 0x84, Raw: "\x84"},
+		}},
+		{"CSI raw", "\x9b2J", []Action{
+			{Type: ActionCSIDispatch, Params: []Param{{2}}, Final: 'J', Raw: "\x9b2J"},
+		}},
+		{"OSC with 8-bit ST", "\x9d2;t\x9c", []Action{
+			{Type: ActionOSCDispatch, Data: "2;t", Raw: "\x9d2;t\x9c"},
+		}},
+	}
+	for _, tt := range tests {
+		p := &Parser{EightBit: true}
+		if got := p.Parse([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse(%q) = %+v, want %+v", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// ExampleC1Writer demonstrates emitting 8-bit controls to a terminal in 8-bit mode
+func ExampleC1Writer() {
+	fmt.Print(Select8BitControls)
+	w := NewC1Writer(os.Stdout)
+	fmt.Fprint(w, CursorPosition(1, 1)+"Hello")
+	w.Flush()
+}
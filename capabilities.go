@@ -0,0 +1,77 @@
+package terminal_go
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RequestCapabilities (XTGETTCAP) asks the terminal for the values of
+// terminfo capabilities, such as "Co" or "colors" for the number of colors
+// and "RGB" for direct color, without relying on the local terminfo
+// database. Names are sent hex encoded. ParseCapabilities decodes the reply
+func RequestCapabilities(names ...string) string {
+	encoded := make([]string, len(names))
+	for i, name := range names {
+		encoded[i] = fmt.Sprintf("%X", name)
+	}
+	return "\033P+q" + strings.Join(encoded, ";") + "\033\\"
+}
+
+// ParseRequestCapabilities parses a sequence produced by RequestCapabilities
+func ParseRequestCapabilities(s string) (names []string, err error) {
+	spec := sequenceSpec{name: "RequestCapabilities", typ: ActionDCSHook, intermediates: "+", final: 'q'}
+	a, err := spec.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range strings.Split(a.Data, ";") {
+		name, err := hex.DecodeString(field)
+		if err != nil {
+			return nil, spec.error(s)
+		}
+		names = append(names, string(name))
+	}
+	return names, nil
+}
+
+// ParseCapabilities parses the replies to RequestCapabilities, DCS 1 + r
+// name=value ST for each capability the terminal knows and DCS 0 + r name
+// ST for the others. s may hold several replies, as terminals answer each
+// name separately. The map holds the decoded value of each known
+// capability; boolean capabilities have an empty value
+func ParseCapabilities(s string) (map[string]string, error) {
+	spec := sequenceSpec{name: "Capabilities", typ: ActionDCSHook, intermediates: "+", final: 'r', maxParams: 1}
+	tokens := DecodeString(s)
+	if len(tokens) == 0 {
+		return nil, spec.error(s)
+	}
+	caps := make(map[string]string)
+	for _, tok := range tokens {
+		a, err := spec.parse(tok.Raw)
+		if err != nil {
+			return nil, spec.error(s)
+		}
+		switch a.Param(0, OmittedParam) {
+		case 0:
+			// The terminal does not know the capabilities
+			continue
+		case 1:
+		default:
+			return nil, spec.error(s)
+		}
+		for _, field := range strings.Split(a.Data, ";") {
+			hexName, hexValue, _ := strings.Cut(field, "=")
+			name, err := hex.DecodeString(hexName)
+			if err != nil || len(name) == 0 {
+				return nil, spec.error(s)
+			}
+			value, err := hex.DecodeString(hexValue)
+			if err != nil {
+				return nil, spec.error(s)
+			}
+			caps[string(name)] = string(value)
+		}
+	}
+	return caps, nil
+}
@@ -0,0 +1,65 @@
+package terminal_go
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestRequestCapabilities verifies the XTGETTCAP emitter and its parser
+func TestRequestCapabilities(t *testing.T) {
+	seq := RequestCapabilities("Co", "RGB", "TN")
+	if want := "\033P+q436F;524742;544E\033\\"; seq != want {
+		t.Errorf("RequestCapabilities() = %q, want %q", seq, want)
+	}
+	names, err := ParseRequestCapabilities(seq)
+	if want := []string{"Co", "RGB", "TN"}; err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("ParseRequestCapabilities(%q) = %q, %v, want %q, nil", seq, names, err, want)
+	}
+	for _, s := range []string{"\033P+qzz\033\\", "\033P$qm\033\\"} {
+		if _, err := ParseRequestCapabilities(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseRequestCapabilities(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestParseCapabilities verifies decoding of XTGETTCAP replies
+func TestParseCapabilities(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  map[string]string
+	}{
+		{"\033P1+r436F=323536\033\\", map[string]string{"Co": "256"}},
+		// One reply per name, an unknown name and a boolean capability
+		{"\033P1+r544E=787465726D\033\\\033P0+r666F6F\033\\\033P1+r524742\033\\", map[string]string{"TN": "xterm", "RGB": ""}},
+		{"\033P1+r636F6C6F7273=323536;524742=38\033\\", map[string]string{"colors": "256", "RGB": "8"}},
+		{"\033P0+r666F6F\033\\", map[string]string{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCapabilities(tt.reply)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCapabilities(%q) = %q, %v, want %q, nil", tt.reply, got, err, tt.want)
+		}
+	}
+	for _, s := range []string{"", "\033P1+r436F=zz\033\\", "\033P1+r=31\033\\", "\033P2+r436F\033\\", "\033P1+r436F\033\\x", "\033P1$rm\033\\"} {
+		if _, err := ParseCapabilities(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseCapabilities(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestDescribeCapabilities verifies explanations of XTGETTCAP requests and replies
+func TestDescribeCapabilities(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{RequestCapabilities("Co", "RGB"), "XTGETTCAP — request terminfo capabilities Co, RGB"},
+		{"\033P1+r436F=323536\033\\", "XTGETTCAP — terminfo capabilities report Co"},
+		{"\033P0+r666F6F\033\\", "XTGETTCAP — unknown terminfo capabilities foo"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
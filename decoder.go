@@ -0,0 +1,197 @@
+package terminal_go
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// TokenType identifies the kind of a Token
+type TokenType int
+
+const (
+	// TokenText is a run of printable characters
+	TokenText TokenType = iota
+	// TokenControl is a single C0 or C1 control character
+	TokenControl
+	// TokenESC is an escape sequence such as ReverseIndex or SetCharacterSet
+	TokenESC
+	// TokenCSI is a control sequence such as CursorPosition or SetGraphicsRendition
+	TokenCSI
+	// TokenOSC is an operating system command string
+	TokenOSC
+	// TokenDCS is a device control string
+	TokenDCS
+	// TokenString is an SOS, PM or APC string
+	TokenString
+)
+
+var tokenTypeNames = [...]string{
+	TokenText:    "Text",
+	TokenControl: "Control",
+	TokenESC:     "ESC",
+	TokenCSI:     "CSI",
+	TokenOSC:     "OSC",
+	TokenDCS:     "DCS",
+	TokenString:  "String",
+}
+
+// String returns the name of the token type
+func (t TokenType) String() string {
+	if t >= 0 && int(t) < len(tokenTypeNames) {
+		return tokenTypeNames[t]
+	}
+	return "TokenType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Token is a complete piece of terminal output produced by Decoder
+type Token struct {
+	// Type is the kind of the token
+	Type TokenType
+	// Text holds the characters of a TokenText, with invalid UTF-8 replaced by U+FFFD
+	Text string
+	// Action holds the decoded control, sequence or string of any other token.
+	// For TokenDCS it is the hook action with Data set to the whole payload
+	Action Action
+	// Raw holds the bytes the token was decoded from
+	Raw string
+	// Truncated reports that the token exceeded the decoder's MaxStringLength
+	// and the bytes past the limit were discarded from Data and Raw
+	Truncated bool
+}
+
+// DefaultMaxStringLength is the MaxStringLength used by a Decoder that does not set one
+const DefaultMaxStringLength = 1 << 16
+
+// Decoder is an incremental tokenizer for terminal output read from a pty,
+// pipe or socket. It keeps partial sequences and UTF-8 characters between
+// calls to Feed and only returns tokens once they are complete.
+type Decoder struct {
+	// EightBit makes the decoder treat the stream as 8-bit with C1 controls
+	// instead of UTF-8, see Parser.EightBit
+	EightBit bool
+	// MaxStringLength limits how many bytes of a single OSC, DCS, SOS, PM or
+	// APC string are kept, so that an unterminated string cannot grow memory
+	// without bound. Zero means DefaultMaxStringLength.
+	MaxStringLength int
+
+	parser  Parser
+	text    []byte
+	textRaw []byte
+	dcs     *Token
+	dcsData []byte
+	out     []Token
+}
+
+// NewDecoder creates a decoder with the default string length limit
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Feed decodes the next chunk of the stream and returns the tokens completed by it
+func (d *Decoder) Feed(data []byte) []Token {
+	d.parser.EightBit = d.EightBit
+	d.parser.MaxStringLength = d.limit()
+	for _, a := range d.parser.Parse(data) {
+		d.handle(a)
+	}
+	return d.take()
+}
+
+// Flush ends the stream. It returns a trailing partial UTF-8 character as
+// U+FFFD text, discards any unfinished sequence and resets the decoder
+func (d *Decoder) Flush() []Token {
+	for _, a := range d.parser.flush() {
+		d.handle(a)
+	}
+	d.dcs = nil
+	d.dcsData = nil
+	return d.take()
+}
+
+// DecodeString splits a complete string of terminal output into tokens
+func DecodeString(s string) []Token {
+	d := NewDecoder()
+	return append(d.Feed([]byte(s)), d.Flush()...)
+}
+
+func (d *Decoder) limit() int {
+	if d.MaxStringLength > 0 {
+		return d.MaxStringLength
+	}
+	return DefaultMaxStringLength
+}
+
+func (d *Decoder) take() []Token {
+	d.flushText()
+	out := d.out
+	d.out = nil
+	return out
+}
+
+func (d *Decoder) handle(a Action) {
+	switch a.Type {
+	case ActionPrint:
+		d.text = utf8.AppendRune(d.text, a.Rune)
+		d.textRaw = append(d.textRaw, a.Raw...)
+		return
+	case ActionDCSPut:
+		if d.dcs == nil {
+			return
+		}
+		room := d.limit() - len(d.dcs.Raw) - len(d.dcsData)
+		data := a.Data
+		if len(data) > room {
+			data = data[:max(room, 0)]
+			d.dcs.Truncated = true
+		}
+		d.dcsData = append(d.dcsData, data...)
+		return
+	case ActionDCSUnhook:
+		if d.dcs == nil {
+			return
+		}
+		if a.Aborted {
+			// A cancelled string is discarded like any other cancelled
+			// sequence; emitting it would leave it without a terminator
+			d.dcs = nil
+			d.dcsData = nil
+			return
+		}
+		d.dcs.Action.Data = string(d.dcsData)
+		d.dcs.Raw += string(d.dcsData) + a.Raw
+		d.out = append(d.out, *d.dcs)
+		d.dcs = nil
+		d.dcsData = nil
+		return
+	}
+
+	d.flushText()
+	t := Token{Action: a, Raw: a.Raw, Truncated: a.Truncated}
+	switch a.Type {
+	case ActionExecute:
+		t.Type = TokenControl
+	case ActionESCDispatch:
+		t.Type = TokenESC
+	case ActionCSIDispatch:
+		t.Type = TokenCSI
+	case ActionOSCDispatch:
+		t.Type = TokenOSC
+	case ActionStringDispatch:
+		t.Type = TokenString
+	case ActionDCSHook:
+		t.Type = TokenDCS
+		d.dcs = &t
+		d.dcsData = d.dcsData[:0]
+		return
+	}
+	d.out = append(d.out, t)
+}
+
+func (d *Decoder) flushText() {
+	if len(d.text) == 0 {
+		return
+	}
+	d.out = append(d.out, Token{Type: TokenText, Text: string(d.text), Raw: string(d.textRaw)})
+	d.text = d.text[:0]
+	d.textRaw = d.textRaw[:0]
+}
@@ -0,0 +1,157 @@
+package terminal_go
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDecodeString verifies that DecodeString groups output into complete tokens
+func TestDecodeString(t *testing.T) {
+	input := BoldBright + "héllo\n" + "\033]8;;https://example.com\a" + "\033Pq#0;2;0;0;0\033\\" + ReverseIndex
+	want := []struct {
+		typ TokenType
+		raw string
+	}{
+		{TokenCSI, BoldBright},
+		{TokenText, "héllo"},
+		{TokenControl, "\n"},
+		{TokenOSC, "\033]8;;https://example.com\a"},
+		{TokenDCS, "\033Pq#0;2;0;0;0\033\\"},
+		{TokenESC, ReverseIndex},
+	}
+
+	got := DecodeString(input)
+	if len(got) != len(want) {
+		t.Fatalf("DecodeString(%q) returned %d tokens, want %d: %+v", input, len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Type != w.typ || got[i].Raw != w.raw {
+			t.Errorf("token %d = %v %q, want %v %q", i, got[i].Type, got[i].Raw, w.typ, w.raw)
+		}
+	}
+	if got[1].Text != "héllo" {
+		t.Errorf("text token Text = %q, want %q", got[1].Text, "héllo")
+	}
+	if got[4].Action.Data != "#0;2;0;0;0" || got[4].Action.Final != 'q' {
+		t.Errorf("DCS token action = %+v, want final 'q' and data %q", got[4].Action, "#0;2;0;0;0")
+	}
+}
+
+// TestDecoderSplitFeeds verifies that feeding a stream in arbitrary pieces yields the same
+// tokens as feeding it at once, with text only split where the feeds were split
+func TestDecoderSplitFeeds(t *testing.T) {
+	input := SetRGBTextColor(10, 20, 30) + "€uro" + ResetAllAttributes +
+		"\033]52;c;aGVsbG8=\033\\" + "\033P1$r0m\033\\" + "\033_Gi=1\033\\"
+	want := DecodeString(input)
+
+	for split := 1; split < len(input); split++ {
+		d := NewDecoder()
+		var got []Token
+		got = append(got, d.Feed([]byte(input[:split]))...)
+		got = append(got, d.Feed([]byte(input[split:]))...)
+		got = append(got, d.Flush()...)
+		if !reflect.DeepEqual(mergeText(got), want) {
+			t.Errorf("split at %d: got %+v, want %+v", split, got, want)
+		}
+	}
+}
+
+func mergeText(tokens []Token) []Token {
+	var out []Token
+	for _, tok := range tokens {
+		if n := len(out); n > 0 && tok.Type == TokenText && out[n-1].Type == TokenText {
+			out[n-1].Text += tok.Text
+			out[n-1].Raw += tok.Raw
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// TestDecoderIncompleteInput verifies that partial sequences are held back until completed
+// and that Flush turns a dangling partial character into U+FFFD
+func TestDecoderIncompleteInput(t *testing.T) {
+	d := NewDecoder()
+	if got := d.Feed([]byte("\033[38;2;10")); len(got) != 0 {
+		t.Errorf("Feed of partial CSI returned %+v, want nothing", got)
+	}
+	got := d.Feed([]byte(";20;30m\xe2\x82"))
+	if len(got) != 1 || got[0].Raw != SetRGBTextColor(10, 20, 30) {
+		t.Errorf("Feed completing CSI returned %+v, want single CSI token", got)
+	}
+	got = d.Flush()
+	if len(got) != 1 || got[0].Text != "�" || got[0].Raw != "\xe2\x82" {
+		t.Errorf("Flush returned %+v, want U+FFFD text token", got)
+	}
+
+	d.Feed([]byte("\033]0;unterminated"))
+	if got := d.Flush(); len(got) != 0 {
+		t.Errorf("Flush of unterminated OSC returned %+v, want nothing", got)
+	}
+	if got := d.Feed([]byte("ok")); len(got) != 1 || got[0].Text != "ok" {
+		t.Errorf("Feed after Flush returned %+v, want text %q", got, "ok")
+	}
+}
+
+// TestDecoderMaxStringLength verifies that runaway strings are truncated instead of buffered
+func TestDecoderMaxStringLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		suffix string
+	}{
+		{"OSC", "\033]0;", "\a"},
+		{"DCS", "\033Pq", "\033\\"},
+		{"APC", "\033_G", "\033\\"},
+	}
+
+	for _, tt := range tests {
+		d := &Decoder{MaxStringLength: 64}
+		var got []Token
+		got = append(got, d.Feed([]byte(tt.prefix))...)
+		for i := 0; i < 100; i++ {
+			got = append(got, d.Feed([]byte(strings.Repeat("x", 100)))...)
+		}
+		got = append(got, d.Feed([]byte(tt.suffix+"after"))...)
+
+		if len(got) != 2 {
+			t.Fatalf("%s: got %d tokens, want 2: %+v", tt.name, len(got), got)
+		}
+		if !got[0].Truncated {
+			t.Errorf("%s: token not marked truncated", tt.name)
+		}
+		if len(got[0].Raw) > 64+len(tt.suffix) {
+			t.Errorf("%s: raw length %d exceeds limit", tt.name, len(got[0].Raw))
+		}
+		if got[1].Text != "after" {
+			t.Errorf("%s: text after string = %q, want %q", tt.name, got[1].Text, "after")
+		}
+	}
+}
+
+// ExampleDecoder demonstrates decoding a stream that arrives in pieces
+func ExampleDecoder() {
+	d := NewDecoder()
+	for _, chunk := range []string{"\033[38;2;255", ";0;0mred", ResetAllAttributes} {
+		for _, tok := range d.Feed([]byte(chunk)) {
+			fmt.Printf("%v %q\n", tok.Type, tok.Raw)
+		}
+	}
+}
+
+// TestDecoderAbortedDCS verifies that a device control string cancelled by CAN or SUB is discarded
+func TestDecoderAbortedDCS(t *testing.T) {
+	for _, cancel := range []string{"\x18", "\x1a"} {
+		got := DecodeString("\033P1$rab" + cancel + "x")
+		if len(got) != 2 || got[0].Type != TokenControl || got[1].Type != TokenText || got[1].Text != "x" {
+			t.Errorf("DecodeString(DCS cancelled by %q) = %+v, want the control and text only", cancel, got)
+		}
+	}
+	policy := SanitizePolicy{DCS: "q"}
+	if got := policy.Sanitize("\033Pq#0\x18x"); got != "x" {
+		t.Errorf("Sanitize(cancelled DCS) = %q, want %q", got, "x")
+	}
+}
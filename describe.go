@@ -0,0 +1,681 @@
+package terminal_go
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Description is a human-readable explanation of a single control, sequence or run of text
+type Description struct {
+	// Raw holds the bytes being described
+	Raw string
+	// Mnemonic is the standard name of the function, e.g. "CUP" or "DECSET 1049".
+	// It is empty for text
+	Mnemonic string
+	// Summary explains what the function does, e.g. "move cursor to row 5, column 10"
+	Summary string
+	// Constant is the name of the package constant equal to Raw, e.g. "EnterAltScreen", if any
+	Constant string
+}
+
+// String formats the description as "DECSET 1049 — enter alternate screen (EnterAltScreen)"
+func (d Description) String() string {
+	s := d.Mnemonic
+	if d.Summary != "" {
+		if s != "" {
+			s += " — "
+		}
+		s += d.Summary
+	}
+	if d.Constant != "" {
+		s += " (" + d.Constant + ")"
+	}
+	return s
+}
+
+// namedConstants lists the constants of this package in declaration order,
+// so that the first name wins where two constants share a value
+var namedConstants = []struct{ name, value string }{
+	{"ReverseIndex", ReverseIndex},
+	{"SaveCursorPointerInMemory", SaveCursorPointerInMemory},
+	{"RestoreCursorPointerFromMemory", RestoreCursorPointerFromMemory},
+	{"CursorBlinking", CursorBlinking},
+	{"CursorBlinkingDisable", CursorBlinkingDisable},
+	{"ShowCursor", ShowCursor},
+	{"HideCursor", HideCursor},
+	{"EnterAltScreen", EnterAltScreen},
+	{"ExitAltScreen", ExitAltScreen},
+	{"EnableLineWrap", EnableLineWrap},
+	{"DisableLineWrap", DisableLineWrap},
+	{"EraseInDisplay", EraseInDisplay},
+	{"EraseInLine", EraseInLine},
+	{"ScrollUp", ScrollUp},
+	{"ScrollDown", ScrollDown},
+	{"SaveCursorPosition", SaveCursorPosition},
+	{"RestoreCursorPosition", RestoreCursorPosition},
+	{"EnableApplicationCursorKeys", EnableApplicationCursorKeys},
+	{"DisableApplicationCursorKeys", DisableApplicationCursorKeys},
+	{"EnableVirtualTerminalProcessing", EnableVirtualTerminalProcessing},
+	{"ResetAllAttributes", ResetAllAttributes},
+	{"BoldBright", BoldBright},
+	{"NormalIntensity", NormalIntensity},
+	{"Underline", Underline},
+	{"UnderlineDisable", UnderlineDisable},
+	{"Negative", Negative},
+	{"Positive", Positive},
+	{"TabSet", TabSet},
+	{"TabClear", TabClear},
+	{"TabClearAll", TabClearAll},
+	{"DoubleHeightTop", DoubleHeightTop},
+	{"DoubleHeightBottom", DoubleHeightBottom},
+	{"SingleWidthLine", SingleWidthLine},
+	{"DoubleWidthLine", DoubleWidthLine},
+	{"DeviceStatusReport", DeviceStatusReport},
+	{"ApplicationKeypad", ApplicationKeypad},
+	{"NormalKeypad", NormalKeypad},
+	{"AutoWrap", AutoWrap},
+	{"AutoWrapOff", AutoWrapOff},
+	{"ClearAndResetScrollback", ClearAndResetScrollback},
+	{"Select7BitControls", Select7BitControls},
+	{"Select8BitControls", Select8BitControls},
+	{"EnableBracketedPaste", EnableBracketedPaste},
+	{"DisableBracketedPaste", DisableBracketedPaste},
+	{"EnableFocusReporting", EnableFocusReporting},
+	{"DisableFocusReporting", DisableFocusReporting},
+	{"QueryKittyKeyboard", QueryKittyKeyboard},
+	{"ResetModifyOtherKeys", ResetModifyOtherKeys},
+	{"QueryModifyOtherKeys", QueryModifyOtherKeys},
+	{"RequestPrimaryDeviceAttributes", RequestPrimaryDeviceAttributes},
+	{"RequestSecondaryDeviceAttributes", RequestSecondaryDeviceAttributes},
+	{"RequestTertiaryDeviceAttributes", RequestTertiaryDeviceAttributes},
+	{"RequestTerminalVersion", RequestTerminalVersion},
+	{"QueryDefaultForegroundColor", QueryDefaultForegroundColor},
+	{"QueryDefaultBackgroundColor", QueryDefaultBackgroundColor},
+	{"QueryCursorColor", QueryCursorColor},
+	{"ResetDefaultForegroundColor", ResetDefaultForegroundColor},
+	{"ResetDefaultBackgroundColor", ResetDefaultBackgroundColor},
+	{"ResetCursorColor", ResetCursorColor},
+}
+
+// constantName returns the name of the first constant whose value is raw
+func constantName(raw string) string {
+	for _, c := range namedConstants {
+		if c.value == raw {
+			return c.name
+		}
+	}
+	return ""
+}
+
+var controlNames = map[rune][2]string{
+	0x00: {"NUL", "null"},
+	0x05: {"ENQ", "enquiry"},
+	0x07: {"BEL", "bell"},
+	0x08: {"BS", "backspace"},
+	0x09: {"HT", "horizontal tab"},
+	0x0A: {"LF", "line feed"},
+	0x0B: {"VT", "vertical tab"},
+	0x0C: {"FF", "form feed"},
+	0x0D: {"CR", "carriage return"},
+	0x0E: {"SO", "shift out to G1 character set"},
+	0x0F: {"SI", "shift in to G0 character set"},
+	0x18: {"CAN", "cancel sequence"},
+	0x1A: {"SUB", "substitute and cancel sequence"},
+	0x84: {"IND", "index"},
+	0x85: {"NEL", "next line"},
+	0x88: {"HTS", "set tab stop"},
+	0x8D: {"RI", "reverse index"},
+	0x9C: {"ST", "string terminator"},
+}
+
+// escFunctions describes escape sequences without intermediate bytes by final byte
+var escFunctions = map[byte][2]string{
+	'7':  {"DECSC", "save cursor"},
+	'8':  {"DECRC", "restore cursor"},
+	'=':  {"DECKPAM", "application keypad"},
+	'>':  {"DECKPNM", "normal keypad"},
+	'D':  {"IND", "index"},
+	'E':  {"NEL", "next line"},
+	'H':  {"HTS", "set tab stop"},
+	'M':  {"RI", "reverse index"},
+	'N':  {"SS2", "single shift 2"},
+	'O':  {"SS3", "single shift 3"},
+	'c':  {"RIS", "full reset"},
+	'\\': {"ST", "string terminator"},
+}
+
+// lineAttributes describes DEC line attribute sequences (ESC # Ps)
+var lineAttributes = map[byte][2]string{
+	'3': {"DECDHL", "double-height line, top half"},
+	'4': {"DECDHL", "double-height line, bottom half"},
+	'5': {"DECSWL", "single-width line"},
+	'6': {"DECDWL", "double-width line"},
+	'8': {"DECALN", "screen alignment test"},
+}
+
+// decModes names the DEC private modes used with DECSET and DECRST
+var decModes = map[int]string{
+	1:    "application cursor keys",
+	3:    "132 column mode",
+	5:    "reverse video",
+	6:    "origin mode",
+	7:    "auto-wrap",
+	9:    "X10 mouse reporting",
+	12:   "cursor blinking",
+	25:   "cursor visibility",
+	47:   "alternate screen (legacy)",
+	66:   "application keypad",
+	1000: "mouse button reporting",
+	1002: "mouse button-event tracking",
+	1003: "mouse any-event tracking",
+	1004: "focus reporting",
+	1006: "SGR mouse encoding",
+	1015: "URXVT mouse encoding",
+	1016: "SGR-pixel mouse encoding",
+	1047: "alternate screen",
+	1048: "saved cursor",
+	1049: "alternate screen",
+	2004: "bracketed paste",
+	2026: "synchronized output",
+	2027: "grapheme clustering",
+}
+
+// ansiModes names the ANSI modes used with SM and RM
+var ansiModes = map[int]string{
+	2:  "keyboard action mode",
+	4:  "insert mode",
+	12: "send/receive mode",
+	20: "automatic newline",
+}
+
+// csiFunctions describes control sequences without private markers or intermediates by final byte
+var csiFunctions = map[byte]struct {
+	mnemonic string
+	describe func(a Action) string
+}{
+	'@': {"ICH", func(a Action) string { return fmt.Sprintf("insert %d blank characters", a.Param(0, 1)) }},
+	'A': {"CUU", func(a Action) string { return fmt.Sprintf("move cursor up %d lines", a.Param(0, 1)) }},
+	'B': {"CUD", func(a Action) string { return fmt.Sprintf("move cursor down %d lines", a.Param(0, 1)) }},
+	'C': {"CUF", func(a Action) string { return fmt.Sprintf("move cursor forward %d columns", a.Param(0, 1)) }},
+	'D': {"CUB", func(a Action) string { return fmt.Sprintf("move cursor backward %d columns", a.Param(0, 1)) }},
+	'E': {"CNL", func(a Action) string { return fmt.Sprintf("move cursor to start of line %d down", a.Param(0, 1)) }},
+	'F': {"CPL", func(a Action) string { return fmt.Sprintf("move cursor to start of line %d up", a.Param(0, 1)) }},
+	'G': {"CHA", func(a Action) string { return fmt.Sprintf("move cursor to column %d", a.Param(0, 1)) }},
+	'H': {"CUP", func(a Action) string {
+		return fmt.Sprintf("move cursor to row %d, column %d", a.Param(0, 1), a.Param(1, 1))
+	}},
+	'J': {"ED", func(a Action) string {
+		return [...]string{"erase from cursor to end of display", "erase from start of display to cursor",
+			"erase complete display", "erase scrollback buffer"}[clampIndex(a.Param(0, 0), 4)]
+	}},
+	'K': {"EL", func(a Action) string {
+		return [...]string{"erase from cursor to end of line", "erase from start of line to cursor",
+			"erase complete line"}[clampIndex(a.Param(0, 0), 3)]
+	}},
+	'L': {"IL", func(a Action) string { return fmt.Sprintf("insert %d lines", a.Param(0, 1)) }},
+	'M': {"DL", func(a Action) string { return fmt.Sprintf("delete %d lines", a.Param(0, 1)) }},
+	'P': {"DCH", func(a Action) string { return fmt.Sprintf("delete %d characters", a.Param(0, 1)) }},
+	'S': {"SU", func(a Action) string { return fmt.Sprintf("scroll up %d lines", a.Param(0, 1)) }},
+	'T': {"SD", func(a Action) string { return fmt.Sprintf("scroll down %d lines", a.Param(0, 1)) }},
+	'R': {"CPR", func(a Action) string {
+		return fmt.Sprintf("cursor position report: row %d, column %d", a.Param(0, 1), a.Param(1, 1))
+	}},
+	'c': {"DA1", func(a Action) string { return "request primary device attributes" }},
+	'g': {"TBC", func(a Action) string {
+		if a.Param(0, 0) == 3 {
+			return "clear all tab stops"
+		}
+		return "clear tab stop at cursor"
+	}},
+	'h': {"SM", func(a Action) string { return describeModes(a, ansiModes, "enable") }},
+	'l': {"RM", func(a Action) string { return describeModes(a, ansiModes, "disable") }},
+	'm': {"SGR", describeSGR},
+	'n': {"DSR", func(a Action) string {
+		if a.Param(0, 0) == 6 {
+			return "request cursor position"
+		}
+		return "request device status"
+	}},
+	'r': {"DECSTBM", func(a Action) string {
+		if len(a.Params) == 0 {
+			return "reset scrolling region"
+		}
+		return fmt.Sprintf("set scrolling region to lines %d-%d", a.Param(0, 1), a.Param(1, 0))
+	}},
+	's': {"SCOSC", func(a Action) string { return "save cursor position" }},
+	't': {"XTWINOPS", describeWindowManipulation},
+	'u': {"SCORC", func(a Action) string { return "restore cursor position" }},
+	'x': {"DECREQTPARM", func(a Action) string { return "request terminal parameters" }},
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 || i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// Describe explains the first control, sequence or run of text in seq
+func Describe(seq string) Description {
+	tokens := DecodeString(seq)
+	if len(tokens) == 0 {
+		return Description{}
+	}
+	return describeToken(tokens[0])
+}
+
+// DescribeAll explains every control, sequence and run of text in s
+func DescribeAll(s string) []Description {
+	tokens := DecodeString(s)
+	descriptions := make([]Description, len(tokens))
+	for i, tok := range tokens {
+		descriptions[i] = describeToken(tok)
+	}
+	return descriptions
+}
+
+// Dump returns an annotated listing of s with one line per control,
+// sequence or run of text, showing the quoted bytes and their description
+func Dump(s string) string {
+	var b strings.Builder
+	for _, d := range DescribeAll(s) {
+		fmt.Fprintf(&b, "%-24s %s\n", strconv.Quote(d.Raw), d)
+	}
+	return b.String()
+}
+
+func describeToken(tok Token) Description {
+	d := Description{Raw: tok.Raw, Constant: constantName(tok.Raw)}
+	a := tok.Action
+	switch tok.Type {
+	case TokenText:
+		d.Summary = "text " + strconv.Quote(tok.Text)
+	case TokenControl:
+		if name, ok := controlNames[a.Rune]; ok {
+			d.Mnemonic, d.Summary = name[0], name[1]
+		} else {
+			d.Mnemonic, d.Summary = fmt.Sprintf("U+%04X", a.Rune), "control character"
+		}
+	case TokenESC:
+		d.Mnemonic, d.Summary = describeESC(a)
+	case TokenCSI:
+		d.Mnemonic, d.Summary = describeCSI(a)
+	case TokenOSC:
+		d.Mnemonic, d.Summary = describeOSC(a)
+	case TokenDCS:
+		d.Mnemonic, d.Summary = describeDCS(a)
+	case TokenString:
+		d.Mnemonic = map[byte]string{'X': "SOS", '^': "PM", '_': "APC"}[a.Final]
+		d.Summary = fmt.Sprintf("string %q", a.Data)
+	}
+	if tok.Truncated {
+		d.Summary += " (truncated)"
+	}
+	return d
+}
+
+func describeESC(a Action) (string, string) {
+	switch a.Intermediates {
+	case "":
+		if f, ok := escFunctions[a.Final]; ok {
+			return f[0], f[1]
+		}
+	case "#":
+		if f, ok := lineAttributes[a.Final]; ok {
+			return f[0], f[1]
+		}
+	case " ":
+		switch a.Final {
+		case 'F':
+			return "S7C1T", "send 7-bit C1 controls"
+		case 'G':
+			return "S8C1T", "send 8-bit C1 controls"
+		}
+	case "(", ")", "*", "+":
+		g := charsetIntermediates[a.Intermediates]
+		return "SCS", fmt.Sprintf("designate G%d character set %s", g, charsetName(a.Final))
+	}
+	return "ESC " + a.Intermediates + string(a.Final), "unknown escape sequence"
+}
+
+func charsetName(charset byte) string {
+	switch charset {
+	case 'B':
+		return "US ASCII"
+	case '0':
+		return "DEC Special Graphics"
+	case 'A':
+		return "United Kingdom"
+	}
+	return strconv.QuoteRune(rune(charset))
+}
+
+func describeCSI(a Action) (string, string) {
+	switch {
+	case a.Private == 0 && a.Intermediates == "":
+		if f, ok := csiFunctions[a.Final]; ok {
+			return f.mnemonic, f.describe(a)
+		}
+	case a.Private == '?' && a.Intermediates == "" && (a.Final == 'h' || a.Final == 'l'):
+		return describeDECMode(a)
+	case a.Private != 0 && a.Intermediates == "" && a.Final == 'u':
+		return describeKittyKeyboard(a)
+	case a.Private == '>' && a.Intermediates == "" && a.Final == 'm' && a.Param(0, 0) == 4:
+		if len(a.Params) < 2 {
+			return "XTMODKEYS", "reset modifyOtherKeys"
+		}
+		return "XTMODKEYS", fmt.Sprintf("set modifyOtherKeys level %d", a.Param(1, 0))
+	case a.Private == '?' && a.Intermediates == "" && a.Final == 'm' && a.Param(0, 0) == 4:
+		return "XTQMODKEYS", "query modifyOtherKeys level"
+	case a.Private != 0 && a.Intermediates == "" && a.Final == 'c':
+		return describeDeviceAttributes(a)
+	case a.Private == '>' && a.Intermediates == "" && a.Final == 'q' && a.Param(0, 0) == 0:
+		return "XTVERSION", "request terminal name and version"
+	case (a.Private == 0 || a.Private == '?') && a.Intermediates == "$" && (a.Final == 'p' || a.Final == 'y'):
+		return describeModeRequest(a)
+	case a.Private == 0 && a.Intermediates == "!" && a.Final == 'p':
+		return "DECSTR", "soft terminal reset"
+	case a.Private == 0 && a.Intermediates == "\"" && a.Final == 'p':
+		return "DECSCL", fmt.Sprintf("set conformance level %d", a.Param(0, 0))
+	}
+	return "CSI " + sequenceShape(a), "unknown control sequence"
+}
+
+// describeKittyKeyboard explains the kitty keyboard protocol sequences CSI > u, CSI < u, CSI = u and CSI ? u
+func describeKittyKeyboard(a Action) (string, string) {
+	flags := KittyKeyboardFlags(a.Param(0, 0))
+	switch a.Private {
+	case '>':
+		return "CSI > u", "push kitty keyboard flags " + flags.String()
+	case '<':
+		return "CSI < u", fmt.Sprintf("pop %d kitty keyboard flag entries", a.Param(0, 1))
+	case '=':
+		verb, ok := map[int]string{1: "set", 2: "add", 3: "remove"}[a.Param(1, 1)]
+		if !ok {
+			verb = "change"
+		}
+		return "CSI = u", verb + " kitty keyboard flags " + flags.String()
+	case '?':
+		if len(a.Params) == 0 {
+			return "CSI ? u", "query kitty keyboard flags"
+		}
+		return "CSI ? u", "kitty keyboard flags report: " + flags.String()
+	}
+	return "CSI " + sequenceShape(a), "unknown control sequence"
+}
+
+// describeDeviceAttributes explains the DA2 and DA3 requests and the DA1 and DA2 replies
+func describeDeviceAttributes(a Action) (string, string) {
+	switch a.Private {
+	case '?':
+		if attrs, err := ParsePrimaryDeviceAttributes(a.Raw); err == nil {
+			return "DA1", "primary device attributes report: " + attrs.String()
+		}
+	case '>':
+		if a.Param(0, 0) == 0 && len(a.Params) <= 1 {
+			return "DA2", "request secondary device attributes"
+		}
+		if attrs, err := ParseSecondaryDeviceAttributes(a.Raw); err == nil {
+			return "DA2", fmt.Sprintf("secondary device attributes report: terminal type %d, firmware version %d",
+				attrs.TerminalType, attrs.FirmwareVersion)
+		}
+	case '=':
+		if a.Param(0, 0) == 0 && len(a.Params) <= 1 {
+			return "DA3", "request tertiary device attributes"
+		}
+	}
+	return "CSI " + sequenceShape(a), "unknown control sequence"
+}
+
+// describeModeRequest explains DECRQM requests and DECRPM replies for ANSI and DEC private modes
+func describeModeRequest(a Action) (string, string) {
+	mode := a.Param(0, 0)
+	names, kind := ansiModes, "mode"
+	if a.Private == '?' {
+		names, kind = decModes, "DEC private mode"
+	}
+	subject := fmt.Sprintf("%s %d", kind, mode)
+	if name, ok := names[mode]; ok {
+		subject += " (" + name + ")"
+	}
+	if a.Final == 'p' {
+		return "DECRQM", "request state of " + subject
+	}
+	return "DECRPM", fmt.Sprintf("%s is %s", subject, ModeState(a.Param(1, 0)))
+}
+
+// sequenceShape renders the private marker, intermediates and final byte of a sequence
+func sequenceShape(a Action) string {
+	var b strings.Builder
+	if a.Private != 0 {
+		b.WriteByte(a.Private)
+	}
+	b.WriteString(a.Intermediates)
+	b.WriteByte(a.Final)
+	return b.String()
+}
+
+func describeDECMode(a Action) (string, string) {
+	mnemonic, verb := "DECSET", "enable"
+	if a.Final == 'l' {
+		mnemonic, verb = "DECRST", "disable"
+	}
+	var nums []string
+	for i := range a.Params {
+		nums = append(nums, strconv.Itoa(a.Param(i, 0)))
+	}
+	if len(a.Params) == 1 && a.Param(0, 0) == 1049 {
+		if a.Final == 'h' {
+			return mnemonic + " 1049", "enter alternate screen"
+		}
+		return mnemonic + " 1049", "exit alternate screen"
+	}
+	return mnemonic + " " + strings.Join(nums, ";"), describeModes(a, decModes, verb)
+}
+
+func describeModes(a Action, names map[int]string, verb string) string {
+	var parts []string
+	for i := range a.Params {
+		mode := a.Param(i, 0)
+		if name, ok := names[mode]; ok {
+			parts = append(parts, verb+" "+name)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s mode %d", verb, mode))
+		}
+	}
+	if len(parts) == 0 {
+		return verb + " mode"
+	}
+	return strings.Join(parts, ", ")
+}
+
+var sgrAttributes = map[int]string{
+	0:  "reset",
+	1:  "bold",
+	2:  "faint",
+	3:  "italic",
+	4:  "underline",
+	5:  "blink",
+	7:  "negative",
+	8:  "invisible",
+	9:  "crossed out",
+	21: "double underline",
+	22: "normal intensity",
+	23: "not italic",
+	24: "no underline",
+	25: "no blink",
+	27: "positive",
+	28: "visible",
+	29: "not crossed out",
+	39: "default foreground",
+	49: "default background",
+}
+
+var colorNames = [...]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+func describeSGR(a Action) string {
+	if len(a.Params) == 0 {
+		return "reset"
+	}
+	var parts []string
+	for i := 0; i < len(a.Params); i++ {
+		p := a.Param(i, 0)
+		switch {
+		case p == 38 || p == 48:
+			target := "foreground"
+			if p == 48 {
+				target = "background"
+			}
+			switch {
+			case len(a.Params[i]) > 1:
+				parts = append(parts, target+" "+describeColorSubs(a.Params[i]))
+			case a.Param(i+1, 0) == 5:
+				parts = append(parts, fmt.Sprintf("%s color %d", target, a.Param(i+2, 0)))
+				i += 2
+			case a.Param(i+1, 0) == 2:
+				parts = append(parts, fmt.Sprintf("%s RGB %d,%d,%d", target, a.Param(i+2, 0), a.Param(i+3, 0), a.Param(i+4, 0)))
+				i += 4
+			default:
+				parts = append(parts, target+" color")
+			}
+		case p >= 30 && p <= 37:
+			parts = append(parts, "foreground "+colorNames[p-30])
+		case p >= 40 && p <= 47:
+			parts = append(parts, "background "+colorNames[p-40])
+		case p >= 90 && p <= 97:
+			parts = append(parts, "foreground bright "+colorNames[p-90])
+		case p >= 100 && p <= 107:
+			parts = append(parts, "background bright "+colorNames[p-100])
+		default:
+			if name, ok := sgrAttributes[p]; ok {
+				parts = append(parts, name)
+			} else {
+				parts = append(parts, fmt.Sprintf("attribute %d", p))
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeColorSubs describes a colon-separated color such as 38:2::10:20:30 or 38:5:1
+func describeColorSubs(p Param) string {
+	switch p.Sub(0, 0) {
+	case 5:
+		return fmt.Sprintf("color %d", p.Sub(1, 0))
+	case 2:
+		// The color space identifier is optional when all four sub-parameters are present
+		if len(p) >= 6 {
+			return fmt.Sprintf("RGB %d,%d,%d", p.Sub(2, 0), p.Sub(3, 0), p.Sub(4, 0))
+		}
+		return fmt.Sprintf("RGB %d,%d,%d", p.Sub(1, 0), p.Sub(2, 0), p.Sub(3, 0))
+	}
+	return "color"
+}
+
+var windowOperations = map[int]string{
+	1: "de-iconify window",
+	2: "iconify window",
+	5: "raise window",
+	6: "lower window",
+	7: "refresh window",
+	9: "maximize or restore window",
+}
+
+func describeWindowManipulation(a Action) string {
+	ps := a.Param(0, 0)
+	switch ps {
+	case 3:
+		return fmt.Sprintf("move window to %d,%d", a.Param(1, 0), a.Param(2, 0))
+	case 4:
+		return fmt.Sprintf("resize window to %dx%d pixels", a.Param(2, 0), a.Param(1, 0))
+	case 8:
+		return fmt.Sprintf("resize window to %d rows, %d columns", a.Param(1, 0), a.Param(2, 0))
+	}
+	if name, ok := windowOperations[ps]; ok {
+		return name
+	}
+	return fmt.Sprintf("window operation %d", ps)
+}
+
+func describeOSC(a Action) (string, string) {
+	command, ok := oscCommand(a.Data)
+	if !ok {
+		return "OSC", fmt.Sprintf("unknown command %q", a.Data)
+	}
+	mnemonic := "OSC " + strconv.Itoa(command)
+	_, arg, _ := strings.Cut(a.Data, ";")
+	switch command {
+	case 0:
+		return mnemonic, fmt.Sprintf("set icon name and window title to %q", arg)
+	case 1:
+		return mnemonic, fmt.Sprintf("set icon name to %q", arg)
+	case 2:
+		return mnemonic, fmt.Sprintf("set window title to %q", arg)
+	case 4:
+		return mnemonic, "change palette color " + arg
+	case 8:
+		_, uri, _ := strings.Cut(arg, ";")
+		if uri == "" {
+			return mnemonic, "end hyperlink"
+		}
+		return mnemonic, fmt.Sprintf("start hyperlink to %q", uri)
+	case 10, 11, 12:
+		what := [...]string{"default foreground color", "default background color", "cursor color"}[command-10]
+		if arg == "?" {
+			return mnemonic, "query " + what
+		}
+		return mnemonic, fmt.Sprintf("set %s to %s", what, arg)
+	case 110, 111, 112:
+		return mnemonic, "reset " + [...]string{"default foreground color", "default background color", "cursor color"}[command-110]
+	case 52:
+		return mnemonic, "set or query clipboard"
+	}
+	return mnemonic, "operating system command"
+}
+
+func describeDCS(a Action) (string, string) {
+	switch {
+	case a.Intermediates == "" && a.Final == 'q':
+		return "DCS q", "sixel graphics"
+	case a.Intermediates == "$" && a.Final == 'q':
+		return "DECRQSS", fmt.Sprintf("request setting %q", a.Data)
+	case a.Intermediates == "$" && a.Final == 'r':
+		if a.Param(0, 0) != 1 {
+			return "DECRPSS", "setting report: request not recognized"
+		}
+		return "DECRPSS", fmt.Sprintf("setting report %q", a.Data)
+	case a.Intermediates == "+" && a.Final == 'q':
+		return "XTGETTCAP", "request terminfo capabilities " + describeCapabilityNames(a.Data)
+	case a.Intermediates == "+" && a.Final == 'r':
+		if a.Param(0, 0) != 1 {
+			return "XTGETTCAP", "unknown terminfo capabilities " + describeCapabilityNames(a.Data)
+		}
+		return "XTGETTCAP", "terminfo capabilities report " + describeCapabilityNames(a.Data)
+	case a.Intermediates == "!" && a.Final == '|':
+		return "DECRPTUI", fmt.Sprintf("tertiary device attributes report: unit ID %q", a.Data)
+	case a.Private == '>' && a.Intermediates == "" && a.Final == '|':
+		return "XTVERSION", fmt.Sprintf("terminal version report: %q", a.Data)
+	case a.Intermediates == "" && a.Final == '|':
+		return "DECUDK", "define user keys"
+	case a.Intermediates == "" && a.Final == '{':
+		return "DECDLD", "download soft font"
+	}
+	return "DCS " + sequenceShape(a), "device control string"
+}
+
+// describeCapabilityNames decodes the hex capability names of an XTGETTCAP
+// request or reply, e.g. "Co, RGB"
+func describeCapabilityNames(data string) string {
+	var names []string
+	for _, field := range strings.Split(data, ";") {
+		hexName, _, _ := strings.Cut(field, "=")
+		name, err := hex.DecodeString(hexName)
+		if err != nil {
+			return strconv.Quote(data)
+		}
+		names = append(names, string(name))
+	}
+	return strings.Join(names, ", ")
+}
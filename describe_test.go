@@ -0,0 +1,84 @@
+package terminal_go
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestDescribe verifies that Describe explains sequences emitted by this package
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{EnterAltScreen, "DECSET 1049 — enter alternate screen (EnterAltScreen)"},
+		{ExitAltScreen, "DECRST 1049 — exit alternate screen (ExitAltScreen)"},
+		{HideCursor, "DECRST 25 — disable cursor visibility (HideCursor)"},
+		{AutoWrap, "DECSET 7 — enable auto-wrap (EnableLineWrap)"},
+		{EraseInDisplay, "ED — erase complete display (EraseInDisplay)"},
+		{CursorPosition(5, 10), "CUP — move cursor to row 5, column 10"},
+		{CursorUp(3), "CUU — move cursor up 3 lines"},
+		{SetTextColor(1), "SGR — foreground color 1"},
+		{SetRGBBackgroundColor(255, 0, 0), "SGR — background RGB 255,0,0"},
+		{SetGraphicsRendition(1, 4, 31), "SGR — bold, underline, foreground red"},
+		{"\033[38:2::10:20:30m", "SGR — foreground RGB 10,20,30"},
+		{ResetAllAttributes, "SGR — reset (ResetAllAttributes)"},
+		{SetMode(4), "SM — enable insert mode"},
+		{WindowManipulation(8, 24, 80), "XTWINOPS — resize window to 24 rows, 80 columns"},
+		{SetScrollingRegion(1, 10), "DECSTBM — set scrolling region to lines 1-10"},
+		{RequestCursorPosition(), "DSR — request cursor position (DeviceStatusReport)"},
+		{ReportCursorPosition(5, 10), "CPR — cursor position report: row 5, column 10"},
+		{DesignateCharacterSet(0, '0'), "SCS — designate G0 character set DEC Special Graphics"},
+		{SoftTerminalReset(), "DECSTR — soft terminal reset"},
+		{SetConformanceLevel(2), "DECSCL — set conformance level 2"},
+		{ReverseIndex, "RI — reverse index (ReverseIndex)"},
+		{SaveCursorPointerInMemory, "DECSC — save cursor (SaveCursorPointerInMemory)"},
+		{DoubleHeightTop, "DECDHL — double-height line, top half (DoubleHeightTop)"},
+		{TabClearAll, "TBC — clear all tab stops (TabClearAll)"},
+		{"\033]2;build\a", `OSC 2 — set window title to "build"`},
+		{"\033]8;;https://example.com\033\\", `OSC 8 — start hyperlink to "https://example.com"`},
+		{"\r", "CR — carriage return"},
+		{"hello", `text "hello"`},
+		{"\033[5;7z", "CSI z — unknown control sequence"},
+	}
+
+	for _, tt := range tests {
+		if got := Describe(tt.input).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestDescribeAll verifies that a whole stream is split and described piece by piece
+func TestDescribeAll(t *testing.T) {
+	input := EnterAltScreen + EraseInDisplay + CursorPosition(5, 10) + "Hi"
+	got := DescribeAll(input)
+	want := []string{"DECSET 1049", "ED", "CUP", ""}
+	if len(got) != len(want) {
+		t.Fatalf("DescribeAll(%q) returned %d descriptions, want %d", input, len(got), len(want))
+	}
+	for i, d := range got {
+		if d.Mnemonic != want[i] {
+			t.Errorf("description %d mnemonic = %q, want %q", i, d.Mnemonic, want[i])
+		}
+	}
+
+	dump := Dump(input)
+	if lines := strings.Count(dump, "\n"); lines != 4 {
+		t.Errorf("Dump(%q) has %d lines, want 4:\n%s", input, lines, dump)
+	}
+	if !strings.Contains(dump, `"\x1b[5;10H"`) || !strings.Contains(dump, "move cursor to row 5, column 10") {
+		t.Errorf("Dump(%q) is missing the annotated CUP line:\n%s", input, dump)
+	}
+}
+
+// ExampleDescribe demonstrates explaining a single sequence
+func ExampleDescribe() {
+	fmt.Println(Describe("\033[?1049h"))
+}
+
+// ExampleDump demonstrates an annotated dump of captured output
+func ExampleDump() {
+	fmt.Print(Dump("\033[?1049h\033[2J\033[5;10HHello"))
+}
@@ -0,0 +1,204 @@
+package terminal_go
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// RequestPrimaryDeviceAttributes (DA1) asks for the terminal's conformance
+	// level and features. Every terminal answers it, which makes it the
+	// sentinel Query relies on. ParsePrimaryDeviceAttributes decodes the reply
+	RequestPrimaryDeviceAttributes = "\033[c"
+	// RequestSecondaryDeviceAttributes (DA2) asks for the terminal's type and
+	// firmware version. ParseSecondaryDeviceAttributes decodes the reply
+	RequestSecondaryDeviceAttributes = "\033[>c"
+	// RequestTertiaryDeviceAttributes (DA3) asks for the terminal's unit ID.
+	// ParseTertiaryDeviceAttributes decodes the reply
+	RequestTertiaryDeviceAttributes = "\033[=c"
+)
+
+// DeviceFeature is an extension a terminal lists in its Primary Device
+// Attributes reply
+type DeviceFeature int
+
+// The features defined by DEC terminals and xterm
+const (
+	Feature132Columns            DeviceFeature = 1
+	FeaturePrinter               DeviceFeature = 2
+	FeatureReGIS                 DeviceFeature = 3
+	FeatureSixel                 DeviceFeature = 4
+	FeatureSelectiveErase        DeviceFeature = 6
+	FeatureSoftCharacterSets     DeviceFeature = 7
+	FeatureUserDefinedKeys       DeviceFeature = 8
+	FeatureNationalCharacterSets DeviceFeature = 9
+	FeatureTechnicalCharacters   DeviceFeature = 15
+	FeatureLocatorPort           DeviceFeature = 16
+	FeatureStateInterrogation    DeviceFeature = 17
+	FeatureWindowing             DeviceFeature = 18
+	FeatureHorizontalScrolling   DeviceFeature = 21
+	FeatureANSIColor             DeviceFeature = 22
+	FeatureRectangularEditing    DeviceFeature = 28
+	FeatureTextLocator           DeviceFeature = 29
+)
+
+var deviceFeatureNames = map[DeviceFeature]string{
+	Feature132Columns:            "132-columns",
+	FeaturePrinter:               "printer",
+	FeatureReGIS:                 "regis",
+	FeatureSixel:                 "sixel",
+	FeatureSelectiveErase:        "selective-erase",
+	FeatureSoftCharacterSets:     "soft-character-sets",
+	FeatureUserDefinedKeys:       "user-defined-keys",
+	FeatureNationalCharacterSets: "national-character-sets",
+	FeatureTechnicalCharacters:   "technical-characters",
+	FeatureLocatorPort:           "locator-port",
+	FeatureStateInterrogation:    "state-interrogation",
+	FeatureWindowing:             "windowing",
+	FeatureHorizontalScrolling:   "horizontal-scrolling",
+	FeatureANSIColor:             "ansi-color",
+	FeatureRectangularEditing:    "rectangular-editing",
+	FeatureTextLocator:           "text-locator",
+}
+
+// String returns the feature's name, e.g. "sixel", or "feature N" if it is unknown
+func (f DeviceFeature) String() string {
+	if name, ok := deviceFeatureNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("feature %d", int(f))
+}
+
+// PrimaryDeviceAttributes is the reply to RequestPrimaryDeviceAttributes,
+// CSI ? Ps ; Ps ... c
+type PrimaryDeviceAttributes struct {
+	// ConformanceLevel is the highest conformance level the terminal
+	// supports, from 1 for a VT100 to 5 for a VT500
+	ConformanceLevel int
+	// Features lists the extensions the terminal supports. Terminals that
+	// identify as a VT100 or VT102 report options instead, which are not
+	// included
+	Features []DeviceFeature
+}
+
+// Has reports whether the terminal lists feature f
+func (a PrimaryDeviceAttributes) Has(f DeviceFeature) bool {
+	return slices.Contains(a.Features, f)
+}
+
+// String renders the attributes, e.g. "level 4: 132-columns|sixel"
+func (a PrimaryDeviceAttributes) String() string {
+	names := make([]string, len(a.Features))
+	for i, f := range a.Features {
+		names[i] = f.String()
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("level %d", a.ConformanceLevel)
+	}
+	return fmt.Sprintf("level %d: %s", a.ConformanceLevel, strings.Join(names, "|"))
+}
+
+// ParsePrimaryDeviceAttributes parses the reply to RequestPrimaryDeviceAttributes
+func ParsePrimaryDeviceAttributes(s string) (PrimaryDeviceAttributes, error) {
+	spec := sequenceSpec{name: "PrimaryDeviceAttributes", typ: ActionCSIDispatch, private: '?', final: 'c', maxParams: maxParams}
+	a, err := spec.parse(s)
+	if err != nil {
+		return PrimaryDeviceAttributes{}, err
+	}
+	if len(a.Params) == 0 {
+		return PrimaryDeviceAttributes{}, spec.error(s)
+	}
+	class := a.Param(0, 0)
+	if class < 61 {
+		// VT100 (1), VT101 (1 ; 0), VT102 (6) and VT125 (12)
+		return PrimaryDeviceAttributes{ConformanceLevel: 1}, nil
+	}
+	attrs := PrimaryDeviceAttributes{ConformanceLevel: class - 60}
+	for i := 1; i < len(a.Params); i++ {
+		// Some terminals end the list with a separator, e.g. kitty's 62;
+		if f := a.Param(i, OmittedParam); f != OmittedParam {
+			attrs.Features = append(attrs.Features, DeviceFeature(f))
+		}
+	}
+	return attrs, nil
+}
+
+// SecondaryDeviceAttributes is the reply to RequestSecondaryDeviceAttributes,
+// CSI > Pp ; Pv ; Pc c
+type SecondaryDeviceAttributes struct {
+	// TerminalType identifies the model the terminal claims to be, e.g. 1
+	// for a VT220, 41 for a VT420 or 64 for a VT520
+	TerminalType int
+	// FirmwareVersion is the terminal's version number; emulators put
+	// their own version here, e.g. xterm's patch number
+	FirmwareVersion int
+	// ROMCartridge is the ROM cartridge registration number, always 0 on
+	// emulators
+	ROMCartridge int
+}
+
+// ParseSecondaryDeviceAttributes parses the reply to RequestSecondaryDeviceAttributes
+func ParseSecondaryDeviceAttributes(s string) (SecondaryDeviceAttributes, error) {
+	spec := sequenceSpec{name: "SecondaryDeviceAttributes", typ: ActionCSIDispatch, private: '>', final: 'c', maxParams: 3}
+	a, err := spec.parse(s)
+	if err != nil {
+		return SecondaryDeviceAttributes{}, err
+	}
+	if len(a.Params) < 2 {
+		return SecondaryDeviceAttributes{}, spec.error(s)
+	}
+	return SecondaryDeviceAttributes{
+		TerminalType:    a.Param(0, 0),
+		FirmwareVersion: a.Param(1, 0),
+		ROMCartridge:    a.Param(2, 0),
+	}, nil
+}
+
+// TertiaryDeviceAttributes is the reply to RequestTertiaryDeviceAttributes,
+// DCS ! | D...D ST (DECRPTUI)
+type TertiaryDeviceAttributes struct {
+	// UnitID is the terminal's unit ID as sent, eight hex digits. Emulators
+	// usually send zeros
+	UnitID string
+}
+
+// ParseTertiaryDeviceAttributes parses the reply to RequestTertiaryDeviceAttributes
+func ParseTertiaryDeviceAttributes(s string) (TertiaryDeviceAttributes, error) {
+	spec := sequenceSpec{name: "TertiaryDeviceAttributes", typ: ActionDCSHook, intermediates: "!", final: '|'}
+	a, err := spec.parse(s)
+	if err != nil {
+		return TertiaryDeviceAttributes{}, err
+	}
+	return TertiaryDeviceAttributes{UnitID: a.Data}, nil
+}
+
+// RequestTerminalVersion (XTVERSION) asks for the terminal's name and
+// version. ParseTerminalVersion decodes the reply
+const RequestTerminalVersion = "\033[>0q"
+
+// TerminalVersion is the reply to RequestTerminalVersion, DCS > | text ST
+type TerminalVersion struct {
+	// Text is the reply as the terminal sent it, e.g. "XTerm(388)"
+	Text string
+	// Name and Version are split from Text, which is usually in the form
+	// "name(version)" or "name version". Version is empty if Text is in
+	// neither form
+	Name, Version string
+}
+
+// ParseTerminalVersion parses the reply to RequestTerminalVersion
+func ParseTerminalVersion(s string) (TerminalVersion, error) {
+	spec := sequenceSpec{name: "TerminalVersion", typ: ActionDCSHook, private: '>', final: '|'}
+	a, err := spec.parse(s)
+	if err != nil {
+		return TerminalVersion{}, err
+	}
+	v := TerminalVersion{Text: a.Data, Name: a.Data}
+	if name, version, ok := strings.Cut(a.Data, "("); ok && strings.HasSuffix(version, ")") {
+		v.Name, v.Version = name, strings.TrimSuffix(version, ")")
+	} else if name, version, ok := strings.Cut(a.Data, " "); ok {
+		v.Name, v.Version = name, version
+	}
+	return v, nil
+}
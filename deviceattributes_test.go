@@ -0,0 +1,124 @@
+package terminal_go
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestParsePrimaryDeviceAttributes verifies decoding of DA1 replies
+func TestParsePrimaryDeviceAttributes(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  PrimaryDeviceAttributes
+	}{
+		// xterm
+		{"\033[?64;1;2;6;9;15;16;17;18;21;22;28c", PrimaryDeviceAttributes{ConformanceLevel: 4, Features: []DeviceFeature{
+			Feature132Columns, FeaturePrinter, FeatureSelectiveErase, FeatureNationalCharacterSets,
+			FeatureTechnicalCharacters, FeatureLocatorPort, FeatureStateInterrogation, FeatureWindowing,
+			FeatureHorizontalScrolling, FeatureANSIColor, FeatureRectangularEditing,
+		}}},
+		// kitty
+		{"\033[?62;c", PrimaryDeviceAttributes{ConformanceLevel: 2}},
+		{"\033[?62;;22c", PrimaryDeviceAttributes{ConformanceLevel: 2, Features: []DeviceFeature{FeatureANSIColor}}},
+		{"\033[?65;4c", PrimaryDeviceAttributes{ConformanceLevel: 5, Features: []DeviceFeature{FeatureSixel}}},
+		// VT100 with advanced video option and VT102
+		{"\033[?1;2c", PrimaryDeviceAttributes{ConformanceLevel: 1}},
+		{"\033[?6c", PrimaryDeviceAttributes{ConformanceLevel: 1}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrimaryDeviceAttributes(tt.reply)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePrimaryDeviceAttributes(%q) = %+v, %v, want %+v, nil", tt.reply, got, err, tt.want)
+		}
+	}
+
+	attrs, _ := ParsePrimaryDeviceAttributes("\033[?62;4;22c")
+	if !attrs.Has(FeatureSixel) || attrs.Has(FeatureRectangularEditing) {
+		t.Errorf("Has() on %+v gave wrong results", attrs)
+	}
+	if got, want := attrs.String(), "level 2: sixel|ansi-color"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for _, s := range []string{RequestPrimaryDeviceAttributes, "\033[?c", "\033[>1;2c", "\033[?62;4c\033[?62c"} {
+		if _, err := ParsePrimaryDeviceAttributes(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParsePrimaryDeviceAttributes(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestParseSecondaryDeviceAttributes verifies decoding of DA2 replies
+func TestParseSecondaryDeviceAttributes(t *testing.T) {
+	got, err := ParseSecondaryDeviceAttributes("\033[>41;388;0c")
+	if want := (SecondaryDeviceAttributes{TerminalType: 41, FirmwareVersion: 388}); err != nil || got != want {
+		t.Errorf("ParseSecondaryDeviceAttributes() = %+v, %v, want %+v, nil", got, err, want)
+	}
+	got, err = ParseSecondaryDeviceAttributes("\033[>1;4000;29c")
+	if want := (SecondaryDeviceAttributes{TerminalType: 1, FirmwareVersion: 4000, ROMCartridge: 29}); err != nil || got != want {
+		t.Errorf("ParseSecondaryDeviceAttributes() = %+v, %v, want %+v, nil", got, err, want)
+	}
+	for _, s := range []string{RequestSecondaryDeviceAttributes, "\033[?1;2c", "\033[>1;2;3;4c"} {
+		if _, err := ParseSecondaryDeviceAttributes(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseSecondaryDeviceAttributes(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestParseTertiaryDeviceAttributes verifies decoding of DA3 replies
+func TestParseTertiaryDeviceAttributes(t *testing.T) {
+	got, err := ParseTertiaryDeviceAttributes("\033P!|7E565445\033\\")
+	if err != nil || got.UnitID != "7E565445" {
+		t.Errorf("ParseTertiaryDeviceAttributes() = %+v, %v, want unit ID 7E565445", got, err)
+	}
+	for _, s := range []string{RequestTertiaryDeviceAttributes, "\033P>|xterm\033\\", "\033P!|0\033\\x"} {
+		if _, err := ParseTertiaryDeviceAttributes(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseTertiaryDeviceAttributes(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestDescribeDeviceAttributes verifies explanations of the device attributes requests and replies
+func TestDescribeDeviceAttributes(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{RequestPrimaryDeviceAttributes, "DA1 — request primary device attributes (RequestPrimaryDeviceAttributes)"},
+		{RequestSecondaryDeviceAttributes, "DA2 — request secondary device attributes (RequestSecondaryDeviceAttributes)"},
+		{RequestTertiaryDeviceAttributes, "DA3 — request tertiary device attributes (RequestTertiaryDeviceAttributes)"},
+		{"\033[?62;22c", "DA1 — primary device attributes report: level 2: ansi-color"},
+		{"\033[>41;388;0c", "DA2 — secondary device attributes report: terminal type 41, firmware version 388"},
+		{"\033P!|00000000\033\\", `DECRPTUI — tertiary device attributes report: unit ID "00000000"`},
+		{RequestTerminalVersion, "XTVERSION — request terminal name and version (RequestTerminalVersion)"},
+		{"\033P>|XTerm(388)\033\\", `XTVERSION — terminal version report: "XTerm(388)"`},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
+
+// TestParseTerminalVersion verifies decoding of XTVERSION replies
+func TestParseTerminalVersion(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  TerminalVersion
+	}{
+		{"\033P>|XTerm(388)\033\\", TerminalVersion{Text: "XTerm(388)", Name: "XTerm", Version: "388"}},
+		{"\033P>|kitty(0.35.2)\033\\", TerminalVersion{Text: "kitty(0.35.2)", Name: "kitty", Version: "0.35.2"}},
+		{"\033P>|WezTerm 20240203-110809-5046fc22\033\\", TerminalVersion{Text: "WezTerm 20240203-110809-5046fc22", Name: "WezTerm", Version: "20240203-110809-5046fc22"}},
+		{"\033P>|foot\033\\", TerminalVersion{Text: "foot", Name: "foot"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTerminalVersion(tt.reply)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTerminalVersion(%q) = %+v, %v, want %+v, nil", tt.reply, got, err, tt.want)
+		}
+	}
+	for _, s := range []string{RequestTerminalVersion, "\033P!|00000000\033\\", "\033P>|XTerm(388)"} {
+		if _, err := ParseTerminalVersion(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseTerminalVersion(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
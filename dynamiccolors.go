@@ -0,0 +1,168 @@
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidColor is returned when a color specification cannot be parsed
+var ErrInvalidColor = errors.New("terminal_go: invalid color")
+
+const (
+	// QueryDefaultForegroundColor (OSC 10) asks for the default text color.
+	// ParseDefaultForegroundColor decodes the reply
+	QueryDefaultForegroundColor = "\033]10;?\033\\"
+	// QueryDefaultBackgroundColor (OSC 11) asks for the default background
+	// color. ParseDefaultBackgroundColor decodes the reply
+	QueryDefaultBackgroundColor = "\033]11;?\033\\"
+	// QueryCursorColor (OSC 12) asks for the cursor color.
+	// ParseCursorColor decodes the reply
+	QueryCursorColor = "\033]12;?\033\\"
+
+	// ResetDefaultForegroundColor (OSC 110) restores the configured text color
+	ResetDefaultForegroundColor = "\033]110\033\\"
+	// ResetDefaultBackgroundColor (OSC 111) restores the configured background color
+	ResetDefaultBackgroundColor = "\033]111\033\\"
+	// ResetCursorColor (OSC 112) restores the configured cursor color
+	ResetCursorColor = "\033]112\033\\"
+)
+
+// RGBColor is a 24-bit color with channels from 0 to 255, like the
+// arguments of SetRGBTextColor
+type RGBColor struct {
+	R, G, B int
+}
+
+// String returns the color in hex notation, e.g. "#1e1e2e"
+func (c RGBColor) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Luminance returns the relative luminance of the color as defined by
+// WCAG, from 0 for black to 1 for white
+func (c RGBColor) Luminance() float64 {
+	linear := func(v int) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// IsDark reports whether white text on the color has more contrast than
+// black text, which is the case for a luminance below about 0.18
+func (c RGBColor) IsDark() bool {
+	l := c.Luminance()
+	// Compare the WCAG contrast ratios (1.05 / (l+0.05)) and ((l+0.05) / 0.05)
+	return (l+0.05)*(l+0.05) < 1.05*0.05
+}
+
+// ParseRGBColor parses an X11 color specification as terminals send it in
+// replies: rgb:R/G/B, where each channel has 1 to 4 hex digits, or
+// rgba:R/G/B/A, whose alpha is ignored. Channels are scaled to 0-255
+func ParseRGBColor(spec string) (RGBColor, error) {
+	channels, ok := strings.CutPrefix(spec, "rgb:")
+	n := 3
+	if !ok {
+		channels, ok = strings.CutPrefix(spec, "rgba:")
+		n = 4
+	}
+	fields := strings.Split(channels, "/")
+	if !ok || len(fields) != n {
+		return RGBColor{}, fmt.Errorf("%w: %q", ErrInvalidColor, spec)
+	}
+	var values [3]int
+	for i := range values {
+		field := fields[i]
+		v, err := strconv.ParseUint(field, 16, 16)
+		if err != nil || len(field) > 4 {
+			return RGBColor{}, fmt.Errorf("%w: %q", ErrInvalidColor, spec)
+		}
+		// Scale from len(field) hex digits to 8 bits, rounding
+		limit := uint64(1)<<(4*len(field)) - 1
+		values[i] = int((v*255 + limit/2) / limit)
+	}
+	return RGBColor{R: values[0], G: values[1], B: values[2]}, nil
+}
+
+// setDynamicColor formats OSC command with the color in the form terminals reply with
+func setDynamicColor(command int, c RGBColor) string {
+	return fmt.Sprintf("\033]%d;rgb:%02x/%02x/%02x\033\\", command, c.R, c.G, c.B)
+}
+
+// SetDefaultForegroundColor (OSC 10) changes the default text color
+func SetDefaultForegroundColor(c RGBColor) string {
+	return setDynamicColor(10, c)
+}
+
+// SetDefaultBackgroundColor (OSC 11) changes the default background color
+func SetDefaultBackgroundColor(c RGBColor) string {
+	return setDynamicColor(11, c)
+}
+
+// SetCursorColor (OSC 12) changes the cursor color
+func SetCursorColor(c RGBColor) string {
+	return setDynamicColor(12, c)
+}
+
+// parseDynamicColor parses OSC command with a single color specification
+func parseDynamicColor(s, name string, command int) (RGBColor, error) {
+	spec := sequenceSpec{name: name}
+	actions := ParseString(s)
+	if len(actions) != 1 || actions[0].Type != ActionOSCDispatch {
+		return RGBColor{}, spec.error(s)
+	}
+	prefix := strconv.Itoa(command) + ";"
+	color, ok := strings.CutPrefix(actions[0].Data, prefix)
+	if !ok {
+		return RGBColor{}, spec.error(s)
+	}
+	c, err := ParseRGBColor(color)
+	if err != nil {
+		return RGBColor{}, spec.error(s)
+	}
+	return c, nil
+}
+
+// ParseDefaultForegroundColor parses a sequence produced by
+// SetDefaultForegroundColor, which is also the form of the terminal's reply
+// to QueryDefaultForegroundColor
+func ParseDefaultForegroundColor(s string) (RGBColor, error) {
+	return parseDynamicColor(s, "DefaultForegroundColor", 10)
+}
+
+// ParseDefaultBackgroundColor parses a sequence produced by
+// SetDefaultBackgroundColor, which is also the form of the terminal's reply
+// to QueryDefaultBackgroundColor
+func ParseDefaultBackgroundColor(s string) (RGBColor, error) {
+	return parseDynamicColor(s, "DefaultBackgroundColor", 11)
+}
+
+// ParseCursorColor parses a sequence produced by SetCursorColor, which is
+// also the form of the terminal's reply to QueryCursorColor
+func ParseCursorColor(s string) (RGBColor, error) {
+	return parseDynamicColor(s, "CursorColor", 12)
+}
+
+// HasDarkBackground asks the terminal for its background color with Query
+// and reports whether it is dark, so a program can pick a palette that is
+// readable on it. It returns ErrNoReply if the terminal does not report
+// its background color
+func HasDarkBackground(ctx context.Context, tty io.ReadWriter) (bool, error) {
+	reply, err := Query(ctx, tty, QueryDefaultBackgroundColor)
+	if err != nil {
+		return false, err
+	}
+	c, err := ParseDefaultBackgroundColor(reply)
+	if err != nil {
+		return false, err
+	}
+	return c.IsDark(), nil
+}
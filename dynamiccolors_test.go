@@ -0,0 +1,112 @@
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseRGBColor verifies parsing of X11 color specifications with 1 to 4 hex digits per channel
+func TestParseRGBColor(t *testing.T) {
+	tests := []struct {
+		spec string
+		want RGBColor
+	}{
+		{"rgb:ffff/ffff/ffff", RGBColor{255, 255, 255}},
+		{"rgb:1e1e/1e1e/2e2e", RGBColor{0x1e, 0x1e, 0x2e}},
+		{"rgb:00/80/ff", RGBColor{0, 0x80, 0xff}},
+		{"rgb:f/8/0", RGBColor{255, 136, 0}},
+		{"rgb:fff/000/800", RGBColor{255, 0, 128}},
+		{"rgba:ffff/0000/0000/ffff", RGBColor{255, 0, 0}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRGBColor(tt.spec)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRGBColor(%q) = %v, %v, want %v, nil", tt.spec, got, err, tt.want)
+		}
+	}
+	for _, spec := range []string{"", "#ffffff", "rgb:ff/ff", "rgb:ff/ff/ff/ff", "rgb:fffff/0/0", "rgb://", "rgb:gg/00/00", "rgba:ff/ff/ff"} {
+		if _, err := ParseRGBColor(spec); !errors.Is(err, ErrInvalidColor) {
+			t.Errorf("ParseRGBColor(%q) error = %v, want ErrInvalidColor", spec, err)
+		}
+	}
+}
+
+// TestRGBColorLuminance verifies the luminance and dark background threshold
+func TestRGBColorLuminance(t *testing.T) {
+	tests := []struct {
+		c         RGBColor
+		luminance float64
+		dark      bool
+	}{
+		{RGBColor{0, 0, 0}, 0, true},
+		{RGBColor{255, 255, 255}, 1, false},
+		{RGBColor{0x1e, 0x1e, 0x2e}, 0.0137, true},
+		{RGBColor{0xfd, 0xf6, 0xe3}, 0.9234, false},
+		{RGBColor{0x80, 0x80, 0x80}, 0.2159, false},
+		{RGBColor{0x70, 0x70, 0x70}, 0.1620, true},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Luminance(); got < tt.luminance-0.001 || got > tt.luminance+0.001 {
+			t.Errorf("%v.Luminance() = %.4f, want %.4f", tt.c, got, tt.luminance)
+		}
+		if got := tt.c.IsDark(); got != tt.dark {
+			t.Errorf("%v.IsDark() = %v, want %v", tt.c, got, tt.dark)
+		}
+	}
+}
+
+// TestDynamicColors verifies the OSC 10, 11 and 12 emitters and their parsers
+func TestDynamicColors(t *testing.T) {
+	c := RGBColor{0x1e, 0x1e, 0x2e}
+	if got, want := SetDefaultBackgroundColor(c), "\033]11;rgb:1e/1e/2e\033\\"; got != want {
+		t.Errorf("SetDefaultBackgroundColor() = %q, want %q", got, want)
+	}
+	if got, err := ParseDefaultForegroundColor(SetDefaultForegroundColor(c)); err != nil || got != c {
+		t.Errorf("ParseDefaultForegroundColor() = %v, %v, want %v, nil", got, err, c)
+	}
+	if got, err := ParseCursorColor(SetCursorColor(c)); err != nil || got != c {
+		t.Errorf("ParseCursorColor() = %v, %v, want %v, nil", got, err, c)
+	}
+	// Terminals reply with 16-bit channels and may end the reply with BEL
+	if got, err := ParseDefaultBackgroundColor("\033]11;rgb:fdfd/f6f6/e3e3\a"); err != nil || got != (RGBColor{0xfd, 0xf6, 0xe3}) {
+		t.Errorf("ParseDefaultBackgroundColor() = %v, %v, want #fdf6e3, nil", got, err)
+	}
+	for _, s := range []string{QueryDefaultBackgroundColor, SetDefaultForegroundColor(c), "\033]11;red\033\\", SetBackgroundColor(1)} {
+		if _, err := ParseDefaultBackgroundColor(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseDefaultBackgroundColor(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+
+	tests := []struct {
+		seq, want string
+	}{
+		{QueryDefaultBackgroundColor, "OSC 11 — query default background color (QueryDefaultBackgroundColor)"},
+		{SetCursorColor(c), "OSC 12 — set cursor color to rgb:1e/1e/2e"},
+		{ResetDefaultForegroundColor, "OSC 110 — reset default foreground color (ResetDefaultForegroundColor)"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
+
+// TestHasDarkBackground verifies the background query against fake terminals
+func TestHasDarkBackground(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for reply, want := range map[string]bool{
+		"\033]11;rgb:1e1e/1e1e/2e2e\033\\": true,
+		"\033]11;rgb:fdfd/f6f6/e3e3\a":     false,
+	} {
+		tty := fakeTerminal(t, map[string]string{QueryDefaultBackgroundColor: reply})
+		if dark, err := HasDarkBackground(ctx, tty); err != nil || dark != want {
+			t.Errorf("HasDarkBackground() with reply %q = %v, %v, want %v, nil", reply, dark, err, want)
+		}
+	}
+	if _, err := HasDarkBackground(ctx, fakeTerminal(t, nil)); !errors.Is(err, ErrNoReply) {
+		t.Errorf("HasDarkBackground() without a reply error = %v, want ErrNoReply", err)
+	}
+}
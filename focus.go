@@ -0,0 +1,23 @@
+package terminal_go
+
+// FocusInEvent reports that the terminal gained focus (CSI I).
+// Terminals only send it while EnableFocusReporting is in effect
+type FocusInEvent struct{}
+
+func (FocusInEvent) isEvent() {}
+
+// String returns "focus in"
+func (FocusInEvent) String() string {
+	return "focus in"
+}
+
+// FocusOutEvent reports that the terminal lost focus (CSI O).
+// Terminals only send it while EnableFocusReporting is in effect
+type FocusOutEvent struct{}
+
+func (FocusOutEvent) isEvent() {}
+
+// String returns "focus out"
+func (FocusOutEvent) String() string {
+	return "focus out"
+}
@@ -0,0 +1,35 @@
+package terminal_go
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestDecodeInputFocus verifies decoding of focus reports
+func TestDecodeInputFocus(t *testing.T) {
+	got := DecodeInput("\033[Ix\033[O\033[2I")
+	want := []Event{FocusInEvent{}, KeyEvent{Key: KeyRune, Rune: 'x'}, FocusOutEvent{}, UnknownEvent{Raw: "\033[2I"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeInput = %+v, want %+v", got, want)
+	}
+	if got := Describe(EnableFocusReporting).String(); got != "DECSET 1004 — enable focus reporting (EnableFocusReporting)" {
+		t.Errorf("Describe(EnableFocusReporting) = %q", got)
+	}
+}
+
+func ExampleFocusInEvent() {
+	fmt.Printf("%q\n", EnableFocusReporting)
+	for _, ev := range DecodeInput("\033[O\033[I") {
+		switch ev.(type) {
+		case FocusOutEvent:
+			fmt.Println("pause animations")
+		case FocusInEvent:
+			fmt.Println("resume animations")
+		}
+	}
+	// Output:
+	// "\x1b[?1004h"
+	// pause animations
+	// resume animations
+}
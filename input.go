@@ -0,0 +1,599 @@
+package terminal_go
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// Event is a single piece of terminal input decoded by InputDecoder
+type Event interface {
+	isEvent()
+}
+
+// UnknownEvent is a complete sequence the input decoder does not recognize,
+// such as a reply to a query the program sent
+type UnknownEvent struct {
+	// Raw holds the bytes of the sequence
+	Raw string
+}
+
+func (UnknownEvent) isEvent() {}
+
+// maxInputSequence bounds the length of a CSI or SS3 sequence in input
+const maxInputSequence = 256
+
+// DefaultEscapeTimeout is how long NewInputDecoder waits for the rest of a
+// sequence before reporting a lone ESC as the Escape key. Terminals send a
+// sequence in one write, so its bytes arrive well within this, while a
+// person cannot press Escape and another key this fast
+const DefaultEscapeTimeout = 50 * time.Millisecond
+
+// InputDecoder turns raw bytes read from a terminal into events. It keeps an
+// incomplete sequence between calls to Feed, so a lone ESC at the end of the
+// input is held back until more bytes arrive, EscapeTimeout passes or Flush
+// is called.
+//
+// A program reading the terminal itself should wait for more input no
+// longer than Deadline and then call Expire:
+//
+//	events := d.Feed(buf[:n])
+//	if deadline, ok := d.Deadline(); ok {
+//		// wait for input until deadline, then
+//		events = append(events, d.Expire(time.Now())...)
+//	}
+type InputDecoder struct {
+	// EscapeTimeout is how long incomplete input may wait for the rest of
+	// a sequence. Editors in the vi tradition let the user tune it, since
+	// a long timeout delays Escape and a short one splits sequences sent
+	// over slow connections
+	EscapeTimeout time.Duration
+	// PastePolicy, if set, sanitizes the text of each PasteEvent. Pasted
+	// text can contain anything, including escape sequences meant to be
+	// run by the program and a fake end of paste marker that makes the
+	// rest of the paste arrive as typed keys; PasteSanitizePolicy removes
+	// the former but the latter can only be filtered by the terminal
+	PastePolicy *SanitizePolicy
+
+	// ApplicationKeypad records whether the program enabled DECKPAM
+	// (ApplicationKeypad). The keypad's SS3 p to SS3 y are only read as
+	// digits in application keypad mode; otherwise ESC O starts Alt+O. Set
+	// the field directly or let ObserveOutput track it. Arrow keys need no
+	// such mode: they are understood both as CSI A to CSI D and, as sent
+	// after EnableApplicationCursorKeys, SS3 A to SS3 D
+	ApplicationKeypad bool
+
+	buf        []byte
+	skipString bool
+	pasting    bool
+	paste      []byte
+	lastFeed   time.Time
+	output     *Parser
+
+	// claimReply, if set, reports whether a control sequence that reads as a
+	// key is a reply to a pending query, as a cursor position report can be
+	claimReply func(a Action) bool
+}
+
+// NewInputDecoder creates an input decoder with DefaultEscapeTimeout
+func NewInputDecoder() *InputDecoder {
+	return &InputDecoder{EscapeTimeout: DefaultEscapeTimeout}
+}
+
+// Feed decodes the next chunk of input and returns the events completed by it
+func (d *InputDecoder) Feed(data []byte) []Event {
+	d.buf = append(d.buf, data...)
+	d.lastFeed = time.Now()
+	return d.decode(false)
+}
+
+// Deadline returns the time at which incomplete input should be given up
+// on and passed to Expire, and false if nothing is waiting. A bracketed
+// paste in progress has no deadline
+func (d *InputDecoder) Deadline() (time.Time, bool) {
+	if len(d.buf) == 0 || d.pasting {
+		return time.Time{}, false
+	}
+	return d.lastFeed.Add(d.EscapeTimeout), true
+}
+
+// Expire flushes incomplete input if its deadline is not after now, so a
+// lone ESC becomes KeyEscape once EscapeTimeout has passed without more input
+func (d *InputDecoder) Expire(now time.Time) []Event {
+	deadline, ok := d.Deadline()
+	if !ok || now.Before(deadline) {
+		return nil
+	}
+	return d.Flush()
+}
+
+// Flush interprets any input held back as incomplete, e.g. a lone ESC
+// becomes KeyEscape and ESC followed by a character becomes Alt+character.
+// A bracketed paste stays pending until its end marker arrives
+func (d *InputDecoder) Flush() []Event {
+	return d.decode(true)
+}
+
+// Pending reports whether the decoder holds incomplete input
+func (d *InputDecoder) Pending() bool {
+	return len(d.buf) > 0 || d.pasting
+}
+
+// DecodeInput decodes a complete string of terminal input
+func DecodeInput(s string) []Event {
+	d := NewInputDecoder()
+	return append(d.Feed([]byte(s)), d.Flush()...)
+}
+
+func (d *InputDecoder) decode(force bool) []Event {
+	var events []Event
+	buf := d.buf
+	for len(buf) > 0 {
+		ev, n, ok := d.next(buf, force)
+		if !ok {
+			break
+		}
+		buf = buf[n:]
+		if ev != nil {
+			events = append(events, ev)
+		}
+	}
+	d.buf = append(d.buf[:0], buf...)
+	return events
+}
+
+// next decodes the event at the start of buf and returns it with the number
+// of bytes it used. It returns false if buf holds only the beginning of a
+// sequence and force is not set. The event may be nil for skipped input
+func (d *InputDecoder) next(buf []byte, force bool) (Event, int, bool) {
+	if d.skipString {
+		return d.skipToTerminator(buf)
+	}
+	if d.pasting {
+		return d.pasteText(buf)
+	}
+	b := buf[0]
+	switch {
+	case b == 0x1B:
+		return d.escape(buf, force)
+	case b < 0x20 || b == 0x7F:
+		return controlKey(b), 1, true
+	case b < 0x80:
+		return KeyEvent{Key: KeyRune, Rune: rune(b)}, 1, true
+	}
+	if !utf8.FullRune(buf) && !force {
+		return nil, 0, false
+	}
+	r, size := utf8.DecodeRune(buf)
+	return KeyEvent{Key: KeyRune, Rune: r}, size, true
+}
+
+// controlKey decodes a single C0 control byte or DEL
+func controlKey(b byte) KeyEvent {
+	switch b {
+	case 0x00:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}
+	case '\t':
+		return KeyEvent{Key: KeyTab}
+	case '\r', '\n':
+		return KeyEvent{Key: KeyEnter}
+	case 0x08, 0x7F:
+		return KeyEvent{Key: KeyBackspace}
+	case 0x1B:
+		return KeyEvent{Key: KeyEscape}
+	}
+	if b <= 0x1A {
+		return KeyEvent{Key: KeyRune, Rune: rune('a' + b - 1), Modifiers: ModCtrl}
+	}
+	// 0x1C-0x1F are Ctrl with \ ] ^ _
+	return KeyEvent{Key: KeyRune, Rune: rune(b + 0x40), Modifiers: ModCtrl}
+}
+
+// withAlt adds Alt to a key event decoded after an ESC prefix
+func withAlt(ev Event, raw []byte) Event {
+	if k, ok := ev.(KeyEvent); ok {
+		k.Modifiers |= ModAlt
+		return k
+	}
+	return UnknownEvent{Raw: string(raw)}
+}
+
+func (d *InputDecoder) escape(buf []byte, force bool) (Event, int, bool) {
+	if len(buf) == 1 {
+		if !force {
+			return nil, 0, false
+		}
+		return KeyEvent{Key: KeyEscape}, 1, true
+	}
+
+	switch buf[1] {
+	case '[':
+		return d.csi(buf, force)
+	case 'O':
+		return d.ss3(buf, force)
+	case ']', 'P', '_', '^', 'X':
+		return d.stringSequence(buf, force)
+	}
+
+	// ESC followed by a key is that key with Alt held down
+	ev, n, ok := d.next(buf[1:], force)
+	if !ok {
+		return nil, 0, false
+	}
+	return withAlt(ev, buf[:n+1]), n + 1, true
+}
+
+// scanSequence finds the final byte of a sequence whose parameter and
+// intermediate bytes start at buf[start]. It returns the length of the
+// sequence, or false if buf ends first. A sequence interrupted by a byte
+// that cannot appear in it ends before that byte
+func scanSequence(buf []byte, start int) (int, bool) {
+	for i := start; i < len(buf); i++ {
+		switch c := buf[i]; {
+		case c >= 0x40 && c <= 0x7E:
+			return i + 1, true
+		case c < 0x20 || c > 0x3F || i >= maxInputSequence:
+			// Malformed: report what was read so far as unknown
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (d *InputDecoder) csi(buf []byte, force bool) (Event, int, bool) {
+	// The Linux console sends F1-F5 as CSI [ A to CSI [ E
+	if len(buf) >= 3 && buf[2] == '[' {
+		if len(buf) == 3 {
+			return d.incomplete(buf, force)
+		}
+		if buf[3] >= 'A' && buf[3] <= 'E' {
+			return KeyEvent{Key: KeyF1 + Key(buf[3]-'A')}, 4, true
+		}
+		return UnknownEvent{Raw: string(buf[:3])}, 3, true
+	}
+
+	// A legacy mouse report carries three raw bytes after CSI M
+	if len(buf) >= 3 && buf[2] == 'M' {
+		if len(buf) < 6 {
+			return d.incomplete(buf, force)
+		}
+		return legacyMouseEvent(buf[:6]), 6, true
+	}
+
+	n, ok := scanSequence(buf, 2)
+	if !ok {
+		return d.incomplete(buf, force)
+	}
+	if n < 3 || buf[n-1] < 0x40 {
+		return UnknownEvent{Raw: string(buf[:n])}, n, true
+	}
+	if i := rxvtShiftEnd(buf[:n]); i > 0 {
+		n = i
+	}
+	return d.csiKey(buf[:n]), n, true
+}
+
+// csiKey parses a complete control sequence and translates it into an event
+func (d *InputDecoder) csiKey(seq []byte) Event {
+	raw, last := string(seq), seq[len(seq)-1]
+	if last == '$' {
+		// $ is an intermediate byte to the parser, so parse the rxvt
+		// sequence with the ~ it replaces
+		seq = append(seq[:len(seq)-1:len(seq)-1], '~')
+	}
+	actions := ParseString(string(seq))
+	if len(actions) != 1 || actions[0].Type != ActionCSIDispatch {
+		return UnknownEvent{Raw: raw}
+	}
+	a := actions[0]
+	a.Final, a.Raw = last, raw
+	return d.csiEvent(a)
+}
+
+// rxvtShiftEnd returns the length of an rxvt Shift key sequence such as
+// CSI 2 $ at the start of a sequence scanned as if the $ were an
+// intermediate byte, or 0 if it is not one. CSI Ps $ y is a mode report
+func rxvtShiftEnd(seq []byte) int {
+	for i := 2; i < len(seq); i++ {
+		switch c := seq[i]; {
+		case c == '$' && i > 2 && (i+1 == len(seq) || seq[i+1] != 'y'):
+			return i + 1
+		case c < '0' || c > '9':
+			return 0
+		}
+	}
+	return 0
+}
+
+// incomplete handles input that ends inside a sequence: it waits for more
+// unless force is set, in which case ESC plus one byte becomes Alt+key and
+// anything longer is reported as unknown
+func (d *InputDecoder) incomplete(buf []byte, force bool) (Event, int, bool) {
+	if !force {
+		return nil, 0, false
+	}
+	if len(buf) == 2 {
+		ev, n, _ := d.next(buf[1:], true)
+		return withAlt(ev, buf), n + 1, true
+	}
+	if buf[1] == '[' {
+		if i := rxvtShiftEnd(buf); i > 0 {
+			return d.csiKey(buf[:i]), i, true
+		}
+	}
+	return UnknownEvent{Raw: string(buf)}, len(buf), true
+}
+
+// csiLetterKeys maps the final byte of CSI and SS3 key sequences to keys
+var csiLetterKeys = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'E': KeyBegin,
+	'F': KeyEnd,
+	'H': KeyHome,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// rxvtArrowKeys maps the lower-case arrow finals rxvt sends with Shift (CSI) or Ctrl (SS3)
+var rxvtArrowKeys = map[byte]Key{
+	'a': KeyUp,
+	'b': KeyDown,
+	'c': KeyRight,
+	'd': KeyLeft,
+}
+
+// tildeKeys maps the first parameter of CSI Ps ~ key sequences to keys
+var tildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+	25: KeyF13,
+	26: KeyF14,
+	28: KeyF15,
+	29: KeyF16,
+	31: KeyF17,
+	32: KeyF18,
+	33: KeyF19,
+	34: KeyF20,
+}
+
+// csiEvent translates a complete control sequence into an event
+func (d *InputDecoder) csiEvent(a Action) Event {
+	if d.claimReply != nil && d.claimReply(a) {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	if a.Intermediates == "" && (a.Private == '<' && (a.Final == 'M' || a.Final == 'm') || a.Private == 0 && a.Final == 'M') {
+		return sgrMouseEvent(a)
+	}
+	if a.Private != 0 || a.Intermediates != "" {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	if key, ok := csiLetterKeys[a.Final]; ok {
+		return withModifierParam(KeyEvent{Key: key}, a)
+	}
+	if key, ok := rxvtArrowKeys[a.Final]; ok && len(a.Params) == 0 {
+		return KeyEvent{Key: key, Modifiers: ModShift}
+	}
+	switch a.Final {
+	case 'I':
+		if len(a.Params) == 0 {
+			return FocusInEvent{}
+		}
+	case 'O':
+		if len(a.Params) == 0 {
+			return FocusOutEvent{}
+		}
+	case 'u':
+		return kittyKeyEvent(a)
+	case 'Z':
+		return KeyEvent{Key: KeyTab, Modifiers: ModShift | xtermModifiers(a.Param(1, 1))}
+	case '~', '$', '^', '@':
+		if a.Final == '~' && len(a.Params) == 1 {
+			switch a.Param(0, 0) {
+			case 200:
+				d.pasting = true
+				return nil
+			case 201:
+				// End of a paste that was never started
+				return nil
+			}
+		}
+		if a.Final == '~' && a.Param(0, 0) == 27 {
+			return modifyOtherKeysEvent(a)
+		}
+		key, ok := tildeKeys[a.Param(0, 0)]
+		if !ok {
+			break
+		}
+		// rxvt reports Shift, Ctrl and Ctrl+Shift with its own final bytes
+		ev := withModifierParam(KeyEvent{Key: key}, a)
+		ev.Modifiers |= map[byte]Modifiers{'$': ModShift, '^': ModCtrl, '@': ModCtrl | ModShift}[a.Final]
+		return ev
+	}
+	return UnknownEvent{Raw: a.Raw}
+}
+
+// withModifierParam sets the modifiers and event type of ev from the second
+// parameter of a, as in CSI 1 ; 5 A. The kitty keyboard protocol adds the
+// event type as a sub-parameter, which also selects its modifier bits
+func withModifierParam(ev KeyEvent, a Action) KeyEvent {
+	if len(a.Params) < 2 {
+		return ev
+	}
+	p := a.Params[1]
+	if len(p) > 1 {
+		ev.Modifiers = kittyModifiers(p.Value(1))
+		ev.Type = kittyEventType(p)
+	} else {
+		ev.Modifiers = xtermModifiers(p.Value(1))
+	}
+	return ev
+}
+
+func (d *InputDecoder) ss3(buf []byte, force bool) (Event, int, bool) {
+	n, ok := scanSequence(buf, 2)
+	if !ok {
+		return d.incomplete(buf, force)
+	}
+	raw := buf[:n]
+	if n < 3 || buf[n-1] < 0x40 {
+		return UnknownEvent{Raw: string(raw)}, n, true
+	}
+	final := buf[n-1]
+	// Some terminals put a modifier parameter between SS3 and the final byte
+	mods := Modifiers(0)
+	if n > 3 {
+		param := 0
+		for _, c := range buf[2 : n-1] {
+			if c < '0' || c > '9' {
+				return UnknownEvent{Raw: string(raw)}, n, true
+			}
+			param = param*10 + int(c-'0')
+		}
+		mods = xtermModifiers(param)
+	}
+	if key, ok := csiLetterKeys[final]; ok {
+		return KeyEvent{Key: key, Modifiers: mods}, n, true
+	}
+	if ev, ok := keypadKeys[final]; ok {
+		if !d.ApplicationKeypad && n == 3 {
+			// Without application keypad mode this is Alt+O and another key
+			return KeyEvent{Key: KeyRune, Rune: 'O', Modifiers: ModAlt}, 2, true
+		}
+		ev.Modifiers = mods
+		return ev, n, true
+	}
+	if key, ok := rxvtArrowKeys[final]; ok {
+		return KeyEvent{Key: key, Modifiers: mods | ModCtrl}, n, true
+	}
+	return UnknownEvent{Raw: string(raw)}, n, true
+}
+
+// keypadKeys maps the final byte of the SS3 sequences the numeric keypad
+// sends in application keypad mode to the keys they stand for
+var keypadKeys = map[byte]KeyEvent{
+	'I': {Key: KeyTab},
+	'M': {Key: KeyEnter},
+	'X': {Key: KeyRune, Rune: '='},
+	'j': {Key: KeyRune, Rune: '*'},
+	'k': {Key: KeyRune, Rune: '+'},
+	'l': {Key: KeyRune, Rune: ','},
+	'm': {Key: KeyRune, Rune: '-'},
+	'n': {Key: KeyRune, Rune: '.'},
+	'o': {Key: KeyRune, Rune: '/'},
+	'p': {Key: KeyRune, Rune: '0'},
+	'q': {Key: KeyRune, Rune: '1'},
+	'r': {Key: KeyRune, Rune: '2'},
+	's': {Key: KeyRune, Rune: '3'},
+	't': {Key: KeyRune, Rune: '4'},
+	'u': {Key: KeyRune, Rune: '5'},
+	'v': {Key: KeyRune, Rune: '6'},
+	'w': {Key: KeyRune, Rune: '7'},
+	'x': {Key: KeyRune, Rune: '8'},
+	'y': {Key: KeyRune, Rune: '9'},
+}
+
+// ObserveOutput updates ApplicationKeypad from output the program sends to
+// the terminal, so the decoder follows the mode the program enables.
+// Sequences may be split across calls
+func (d *InputDecoder) ObserveOutput(p []byte) {
+	if d.output == nil {
+		d.output = NewParser()
+		d.output.MaxStringLength = DefaultMaxStringLength
+	}
+	for _, a := range d.output.Parse(p) {
+		switch {
+		case a.Type == ActionESCDispatch && a.Intermediates == "":
+			switch a.Final {
+			case '=':
+				d.ApplicationKeypad = true
+			case '>':
+				d.ApplicationKeypad = false
+			case 'c':
+				d.ApplicationKeypad = false
+			}
+		case a.Type == ActionCSIDispatch && a.Private == 0 && a.Intermediates == "!" && a.Final == 'p':
+			// DECSTR resets the keypad mode
+			d.ApplicationKeypad = false
+		case a.Type == ActionCSIDispatch && a.Private == '?' && a.Intermediates == "" && (a.Final == 'h' || a.Final == 'l'):
+			for i := range a.Params {
+				// DECNKM is the mode form of DECKPAM and DECKPNM
+				if a.Param(i, 0) == 66 {
+					d.ApplicationKeypad = a.Final == 'h'
+				}
+			}
+		}
+	}
+}
+
+// stringSequence consumes an OSC, DCS, APC, PM or SOS string, which in
+// input are replies to queries
+func (d *InputDecoder) stringSequence(buf []byte, force bool) (Event, int, bool) {
+	for i := 2; i < len(buf); i++ {
+		switch buf[i] {
+		case 0x07:
+			return UnknownEvent{Raw: string(buf[:i+1])}, i + 1, true
+		case 0x1B:
+			if i+1 == len(buf) {
+				break
+			}
+			if buf[i+1] == '\\' {
+				return UnknownEvent{Raw: string(buf[:i+2])}, i + 2, true
+			}
+			return UnknownEvent{Raw: string(buf[:i])}, i, true
+		}
+	}
+	if len(buf) > DefaultMaxStringLength {
+		d.skipString = true
+		return UnknownEvent{Raw: string(buf)}, len(buf), true
+	}
+	if !force {
+		return nil, 0, false
+	}
+	if len(buf) == 2 {
+		return d.incomplete(buf, force)
+	}
+	return UnknownEvent{Raw: string(buf)}, len(buf), true
+}
+
+// skipToTerminator discards the rest of an overlong string up to its terminator
+func (d *InputDecoder) skipToTerminator(buf []byte) (Event, int, bool) {
+	for i, b := range buf {
+		switch {
+		case b == 0x07:
+			d.skipString = false
+			return nil, i + 1, true
+		case b == 0x1B && i+1 < len(buf):
+			d.skipString = false
+			if buf[i+1] == '\\' {
+				return nil, i + 2, true
+			}
+			return nil, i, true
+		}
+	}
+	if buf[len(buf)-1] == 0x1B {
+		return nil, len(buf) - 1, len(buf) > 1
+	}
+	return nil, len(buf), true
+}
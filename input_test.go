@@ -0,0 +1,287 @@
+package terminal_go
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDecodeInputKeys verifies the key sequences sent by common terminals
+func TestDecodeInputKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  KeyEvent
+	}{
+		{"a", KeyEvent{Key: KeyRune, Rune: 'a'}},
+		{"é", KeyEvent{Key: KeyRune, Rune: 'é'}},
+		{"\r", KeyEvent{Key: KeyEnter}},
+		{"\t", KeyEvent{Key: KeyTab}},
+		{"\x7f", KeyEvent{Key: KeyBackspace}},
+		{"\x08", KeyEvent{Key: KeyBackspace}},
+		{"\x00", KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}},
+		{"\x01", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCtrl}},
+		{"\x1a", KeyEvent{Key: KeyRune, Rune: 'z', Modifiers: ModCtrl}},
+		{"\x1c", KeyEvent{Key: KeyRune, Rune: '\\', Modifiers: ModCtrl}},
+		{"\x1f", KeyEvent{Key: KeyRune, Rune: '_', Modifiers: ModCtrl}},
+		{"\033", KeyEvent{Key: KeyEscape}},
+		{"\033a", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModAlt}},
+		{"\033\x01", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModAlt | ModCtrl}},
+		{"\033\r", KeyEvent{Key: KeyEnter, Modifiers: ModAlt}},
+		{"\033\033", KeyEvent{Key: KeyEscape, Modifiers: ModAlt}},
+		{"\033[", KeyEvent{Key: KeyRune, Rune: '[', Modifiers: ModAlt}},
+		{"\033O", KeyEvent{Key: KeyRune, Rune: 'O', Modifiers: ModAlt}},
+		{"\033[A", KeyEvent{Key: KeyUp}},
+		{"\033[B", KeyEvent{Key: KeyDown}},
+		{"\033[C", KeyEvent{Key: KeyRight}},
+		{"\033[D", KeyEvent{Key: KeyLeft}},
+		{"\033[H", KeyEvent{Key: KeyHome}},
+		{"\033[F", KeyEvent{Key: KeyEnd}},
+		{"\033[E", KeyEvent{Key: KeyBegin}},
+		{"\033OA", KeyEvent{Key: KeyUp}},
+		{"\033OH", KeyEvent{Key: KeyHome}},
+		{"\033OP", KeyEvent{Key: KeyF1}},
+		{"\033OS", KeyEvent{Key: KeyF4}},
+		{"\033O5P", KeyEvent{Key: KeyF1, Modifiers: ModCtrl}},
+		{"\033Oa", KeyEvent{Key: KeyUp, Modifiers: ModCtrl}},
+		{"\033[1;5A", KeyEvent{Key: KeyUp, Modifiers: ModCtrl}},
+		{"\033[1;2D", KeyEvent{Key: KeyLeft, Modifiers: ModShift}},
+		{"\033[1;3C", KeyEvent{Key: KeyRight, Modifiers: ModAlt}},
+		{"\033[1;8B", KeyEvent{Key: KeyDown, Modifiers: ModCtrl | ModAlt | ModShift}},
+		{"\033[1;9H", KeyEvent{Key: KeyHome, Modifiers: ModMeta}},
+		{"\033[1;2P", KeyEvent{Key: KeyF1, Modifiers: ModShift}},
+		{"\033[a", KeyEvent{Key: KeyUp, Modifiers: ModShift}},
+		{"\033[Z", KeyEvent{Key: KeyTab, Modifiers: ModShift}},
+		{"\033[1~", KeyEvent{Key: KeyHome}},
+		{"\033[2~", KeyEvent{Key: KeyInsert}},
+		{"\033[3~", KeyEvent{Key: KeyDelete}},
+		{"\033[3;5~", KeyEvent{Key: KeyDelete, Modifiers: ModCtrl}},
+		{"\033[4~", KeyEvent{Key: KeyEnd}},
+		{"\033[5~", KeyEvent{Key: KeyPageUp}},
+		{"\033[6~", KeyEvent{Key: KeyPageDown}},
+		{"\033[6;3~", KeyEvent{Key: KeyPageDown, Modifiers: ModAlt}},
+		{"\033[7~", KeyEvent{Key: KeyHome}},
+		{"\033[8~", KeyEvent{Key: KeyEnd}},
+		{"\033[11~", KeyEvent{Key: KeyF1}},
+		{"\033[15~", KeyEvent{Key: KeyF5}},
+		{"\033[15;2~", KeyEvent{Key: KeyF5, Modifiers: ModShift}},
+		{"\033[17~", KeyEvent{Key: KeyF6}},
+		{"\033[21~", KeyEvent{Key: KeyF10}},
+		{"\033[23~", KeyEvent{Key: KeyF11}},
+		{"\033[24~", KeyEvent{Key: KeyF12}},
+		{"\033[25~", KeyEvent{Key: KeyF13}},
+		{"\033[34~", KeyEvent{Key: KeyF20}},
+		{"\033[2$", KeyEvent{Key: KeyInsert, Modifiers: ModShift}},
+		{"\033[5^", KeyEvent{Key: KeyPageUp, Modifiers: ModCtrl}},
+		{"\033[3@", KeyEvent{Key: KeyDelete, Modifiers: ModCtrl | ModShift}},
+		{"\033[[A", KeyEvent{Key: KeyF1}},
+		{"\033[[E", KeyEvent{Key: KeyF5}},
+		{"\033\033[A", KeyEvent{Key: KeyUp, Modifiers: ModAlt}},
+	}
+
+	for _, tt := range tests {
+		got := DecodeInput(tt.input)
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("DecodeInput(%q) = %+v, want [%+v]", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestDecodeInputSplit verifies that sequences split across reads are held back until complete
+func TestDecodeInputSplit(t *testing.T) {
+	input := "a\033[1;5Aé\033OP\033b\033]11;rgb:0/0/0\033\\\033[15~"
+	want := DecodeInput(input)
+	if len(want) != 7 {
+		t.Fatalf("DecodeInput(%q) returned %d events, want 7: %+v", input, len(want), want)
+	}
+
+	for size := 1; size <= len(input); size++ {
+		d := NewInputDecoder()
+		var got []Event
+		for i := 0; i < len(input); i += size {
+			end := min(i+size, len(input))
+			got = append(got, d.Feed([]byte(input[i:end]))...)
+		}
+		if d.Pending() {
+			t.Errorf("chunk size %d: decoder still pending after complete input", size)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("chunk size %d: got %+v, want %+v", size, got, want)
+		}
+	}
+}
+
+// TestInputDecoderFlush verifies that a lone ESC is only reported as Escape on Flush
+func TestInputDecoderFlush(t *testing.T) {
+	d := NewInputDecoder()
+	if got := d.Feed([]byte("x\033")); len(got) != 1 {
+		t.Fatalf("Feed returned %+v, want only the x key", got)
+	}
+	if !d.Pending() {
+		t.Fatal("Pending() = false after a trailing ESC")
+	}
+	got := d.Flush()
+	want := []Event{KeyEvent{Key: KeyEscape}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush() = %+v, want %+v", got, want)
+	}
+	if d.Pending() {
+		t.Error("Pending() = true after Flush")
+	}
+}
+
+// TestInputDecoderEscapeTimeout verifies that a lone ESC becomes the Escape key only once EscapeTimeout passes
+func TestInputDecoderEscapeTimeout(t *testing.T) {
+	d := NewInputDecoder()
+	d.EscapeTimeout = time.Second
+	if _, ok := d.Deadline(); ok {
+		t.Error("Deadline() reported a deadline with nothing pending")
+	}
+
+	before := time.Now()
+	d.Feed([]byte("\033"))
+	deadline, ok := d.Deadline()
+	if !ok || deadline.Before(before.Add(time.Second)) {
+		t.Fatalf("Deadline() = %v, %v, want at least %v", deadline, ok, before.Add(time.Second))
+	}
+	if got := d.Expire(deadline.Add(-time.Millisecond)); len(got) != 0 {
+		t.Errorf("Expire before the deadline = %+v, want no events", got)
+	}
+
+	// The rest of a sequence arriving in time completes it
+	got := d.Feed([]byte("[A"))
+	if want := []Event{KeyEvent{Key: KeyUp}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Feed = %+v, want %+v", got, want)
+	}
+
+	d.Feed([]byte("\033"))
+	deadline, _ = d.Deadline()
+	got = d.Expire(deadline)
+	if want := []Event{KeyEvent{Key: KeyEscape}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expire at the deadline = %+v, want %+v", got, want)
+	}
+
+	// A key typed after the timeout is not combined with the Escape key
+	got = d.Feed([]byte("j"))
+	if want := []Event{KeyEvent{Key: KeyRune, Rune: 'j'}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Feed after the timeout = %+v, want %+v", got, want)
+	}
+}
+
+// TestInputDecoderApplicationKeypad verifies that keypad SS3 sequences are only read as digits in application keypad mode
+func TestInputDecoderApplicationKeypad(t *testing.T) {
+	d := NewInputDecoder()
+	got := append(d.Feed([]byte("\033Oq\033OA")), d.Flush()...)
+	want := []Event{
+		KeyEvent{Key: KeyRune, Rune: 'O', Modifiers: ModAlt},
+		KeyEvent{Key: KeyRune, Rune: 'q'},
+		KeyEvent{Key: KeyUp},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normal keypad: got %+v, want %+v", got, want)
+	}
+
+	// Arrow keys are read the same whatever DECCKM is set to
+	d.ObserveOutput([]byte("hello" + EnableApplicationCursorKeys + ApplicationKeypad[:1]))
+	d.ObserveOutput([]byte(ApplicationKeypad[1:]))
+	if !d.ApplicationKeypad {
+		t.Fatal("ObserveOutput did not enable application keypad mode")
+	}
+	got = d.Feed([]byte("\033Oq\033Oy\033Ok\033OM\033OA"))
+	want = []Event{
+		KeyEvent{Key: KeyRune, Rune: '1'},
+		KeyEvent{Key: KeyRune, Rune: '9'},
+		KeyEvent{Key: KeyRune, Rune: '+'},
+		KeyEvent{Key: KeyEnter},
+		KeyEvent{Key: KeyUp},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("application keypad: got %+v, want %+v", got, want)
+	}
+
+	d.ObserveOutput([]byte(NormalKeypad + DisableApplicationCursorKeys))
+	if d.ApplicationKeypad {
+		t.Error("NormalKeypad did not reset application keypad mode")
+	}
+	d.ObserveOutput([]byte("\033[?66h"))
+	if !d.ApplicationKeypad {
+		t.Error("DECNKM did not enable application keypad mode")
+	}
+	d.ObserveOutput([]byte(SoftTerminalReset()))
+	if d.ApplicationKeypad {
+		t.Error("DECSTR did not reset application keypad mode")
+	}
+}
+
+// TestDecodeInputUnknown verifies that unrecognized sequences are reported whole
+func TestDecodeInputUnknown(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Event
+	}{
+		{"\033[?1;2c", []Event{UnknownEvent{Raw: "\033[?1;2c"}}},
+		{"\033[99~x", []Event{UnknownEvent{Raw: "\033[99~"}, KeyEvent{Key: KeyRune, Rune: 'x'}}},
+		{"\033]11;rgb:0000/0000/0000\a", []Event{UnknownEvent{Raw: "\033]11;rgb:0000/0000/0000\a"}}},
+		{"\033P1$r0m\033\\", []Event{UnknownEvent{Raw: "\033P1$r0m\033\\"}}},
+		{"\033[1\r", []Event{UnknownEvent{Raw: "\033[1"}, KeyEvent{Key: KeyEnter}}},
+	}
+
+	for _, tt := range tests {
+		if got := DecodeInput(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DecodeInput(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestInputDecoderLongString verifies that an unterminated string reply cannot grow the buffer without bound
+func TestInputDecoderLongString(t *testing.T) {
+	d := NewInputDecoder()
+	d.Feed([]byte("\033]"))
+	chunk := []byte(strings.Repeat("x", 4096))
+	for i := 0; i < DefaultMaxStringLength/len(chunk)+2; i++ {
+		d.Feed(chunk)
+	}
+	if len(d.buf) > DefaultMaxStringLength {
+		t.Errorf("buffer grew to %d bytes, want at most %d", len(d.buf), DefaultMaxStringLength)
+	}
+	got := d.Feed([]byte("\aq"))
+	want := []Event{KeyEvent{Key: KeyRune, Rune: 'q'}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Feed after terminator = %+v, want %+v", got, want)
+	}
+}
+
+// TestKeyEventString verifies the rendering of key events
+func TestKeyEventString(t *testing.T) {
+	tests := []struct {
+		ev   KeyEvent
+		want string
+	}{
+		{KeyEvent{Key: KeyRune, Rune: 'a'}, "a"},
+		{KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCtrl}, "ctrl+a"},
+		{KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}, "ctrl+space"},
+		{KeyEvent{Key: KeyEnter, Modifiers: ModAlt}, "alt+enter"},
+		{KeyEvent{Key: KeyF3, Modifiers: ModShift}, "shift+f3"},
+		{KeyEvent{Key: KeyUp, Modifiers: ModShift | ModCtrl | ModAlt | ModMeta}, "ctrl+alt+shift+meta+up"},
+		{KeyEvent{Key: KeyPageDown}, "pgdown"},
+		{KeyEvent{Key: KeyF24}, "f24"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ev.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.ev, got, tt.want)
+		}
+	}
+}
+
+func ExampleDecodeInput() {
+	for _, ev := range DecodeInput("\033[1;5A\x01\033x\033OQ") {
+		fmt.Println(ev)
+	}
+	// Output:
+	// ctrl+up
+	// ctrl+a
+	// alt+x
+	// f2
+}
@@ -0,0 +1,270 @@
+package terminal_go
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Key identifies a key on the keyboard. Keys that produce a character are
+// reported as KeyRune together with the character
+type Key int
+
+const (
+	// KeyRune is a key that produces the character in KeyEvent.Rune
+	KeyRune Key = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	// KeyBegin is the middle key of the keypad (5 with Num Lock off)
+	KeyBegin
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyF13
+	KeyF14
+	KeyF15
+	KeyF16
+	KeyF17
+	KeyF18
+	KeyF19
+	KeyF20
+	KeyF21
+	KeyF22
+	KeyF23
+	KeyF24
+	KeyCapsLock
+	KeyScrollLock
+	KeyNumLock
+	KeyPrintScreen
+	KeyPause
+	KeyMenu
+	// The modifier keys themselves are only reported by the kitty keyboard
+	// protocol with KittyReportAllKeysAsEscapeCodes
+	KeyLeftShift
+	KeyLeftCtrl
+	KeyLeftAlt
+	KeyLeftSuper
+	KeyLeftHyper
+	KeyLeftMeta
+	KeyRightShift
+	KeyRightCtrl
+	KeyRightAlt
+	KeyRightSuper
+	KeyRightHyper
+	KeyRightMeta
+)
+
+var keyNames = map[Key]string{
+	KeyEnter:     "enter",
+	KeyTab:       "tab",
+	KeyBackspace: "backspace",
+	KeyEscape:    "esc",
+	KeyUp:        "up",
+	KeyDown:      "down",
+	KeyRight:     "right",
+	KeyLeft:      "left",
+	KeyHome:      "home",
+	KeyEnd:       "end",
+	KeyPageUp:    "pgup",
+	KeyPageDown:  "pgdown",
+	KeyInsert:    "insert",
+	KeyDelete:    "delete",
+	KeyBegin:     "begin",
+
+	KeyCapsLock:    "capslock",
+	KeyScrollLock:  "scrolllock",
+	KeyNumLock:     "numlock",
+	KeyPrintScreen: "printscreen",
+	KeyPause:       "pause",
+	KeyMenu:        "menu",
+
+	KeyLeftShift:  "leftshift",
+	KeyLeftCtrl:   "leftctrl",
+	KeyLeftAlt:    "leftalt",
+	KeyLeftSuper:  "leftsuper",
+	KeyLeftHyper:  "lefthyper",
+	KeyLeftMeta:   "leftmeta",
+	KeyRightShift: "rightshift",
+	KeyRightCtrl:  "rightctrl",
+	KeyRightAlt:   "rightalt",
+	KeyRightSuper: "rightsuper",
+	KeyRightHyper: "righthyper",
+	KeyRightMeta:  "rightmeta",
+}
+
+// String returns the lower-case name of the key, e.g. "enter" or "f5"
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	if k >= KeyF1 && k <= KeyF24 {
+		return "f" + strconv.Itoa(int(k-KeyF1)+1)
+	}
+	if k == KeyRune {
+		return "rune"
+	}
+	return "Key(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Modifiers is a set of modifier keys held down with a key.
+// The bit values follow the kitty keyboard protocol
+type Modifiers int
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModAlt
+	ModCtrl
+	ModSuper
+	ModHyper
+	ModMeta
+	// ModCapsLock and ModNumLock report lock state rather than a key held
+	// down; only the kitty keyboard protocol sends them
+	ModCapsLock
+	ModNumLock
+)
+
+// modifierNames lists the modifiers in the order String renders them
+var modifierNames = []struct {
+	mod  Modifiers
+	name string
+}{
+	{ModCtrl, "ctrl"},
+	{ModAlt, "alt"},
+	{ModShift, "shift"},
+	{ModSuper, "super"},
+	{ModHyper, "hyper"},
+	{ModMeta, "meta"},
+	{ModCapsLock, "capslock"},
+	{ModNumLock, "numlock"},
+}
+
+// String returns the modifiers joined with "+", e.g. "ctrl+shift"
+func (m Modifiers) String() string {
+	var parts []string
+	for _, n := range modifierNames {
+		if m&n.mod != 0 {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// xtermModifiers converts the modifier parameter of an xterm key sequence,
+// such as the 5 in CSI 1 ; 5 A, to Modifiers. xterm uses the fourth bit
+// for Meta where the kitty protocol uses it for Super
+func xtermModifiers(param int) Modifiers {
+	bits := param - 1
+	if bits <= 0 {
+		return 0
+	}
+	var m Modifiers
+	if bits&1 != 0 {
+		m |= ModShift
+	}
+	if bits&2 != 0 {
+		m |= ModAlt
+	}
+	if bits&4 != 0 {
+		m |= ModCtrl
+	}
+	if bits&8 != 0 {
+		m |= ModMeta
+	}
+	return m
+}
+
+// kittyModifiers converts the modifier parameter of a kitty keyboard
+// protocol sequence, which is the Modifiers bits plus one
+func kittyModifiers(param int) Modifiers {
+	if param <= 1 {
+		return 0
+	}
+	return Modifiers(param - 1)
+}
+
+// KeyEventType tells whether a key was pressed, repeated or released.
+// Terminals only report repeats and releases with the kitty keyboard
+// protocol flag KittyReportEventTypes
+type KeyEventType int
+
+const (
+	// KeyPress is a key being pressed
+	KeyPress KeyEventType = iota
+	// KeyRepeat is a key held down long enough to repeat
+	KeyRepeat
+	// KeyRelease is a key being released
+	KeyRelease
+)
+
+// String returns "press", "repeat" or "release"
+func (t KeyEventType) String() string {
+	switch t {
+	case KeyPress:
+		return "press"
+	case KeyRepeat:
+		return "repeat"
+	case KeyRelease:
+		return "release"
+	}
+	return "KeyEventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// KeyEvent is a key press decoded from terminal input
+type KeyEvent struct {
+	// Key identifies the key
+	Key Key
+	// Rune is the character produced by a KeyRune key
+	Rune rune
+	// Modifiers holds the modifier keys held down with the key
+	Modifiers Modifiers
+	// Type tells whether the key was pressed, repeated or released
+	Type KeyEventType
+	// ShiftedRune is the character the key produces with Shift, and BaseRune
+	// the character of the key in the standard US layout. They are only set
+	// by the kitty keyboard protocol with KittyReportAlternateKeys
+	ShiftedRune rune
+	BaseRune    rune
+	// Text is the text the key generates, only set by the kitty keyboard
+	// protocol with KittyReportAssociatedText
+	Text string
+}
+
+func (KeyEvent) isEvent() {}
+
+// String renders the key as it would be written in a key binding, e.g.
+// "ctrl+a", "alt+enter" or "shift+f3"
+func (k KeyEvent) String() string {
+	var name string
+	switch {
+	case k.Key != KeyRune:
+		name = k.Key.String()
+	case k.Rune == ' ':
+		name = "space"
+	default:
+		name = string(k.Rune)
+	}
+	if k.Modifiers == 0 {
+		return name
+	}
+	return k.Modifiers.String() + "+" + name
+}
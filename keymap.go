@@ -0,0 +1,372 @@
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidKeyBinding is returned when a key binding string cannot be parsed
+var ErrInvalidKeyBinding = errors.New("terminal_go: invalid key binding")
+
+// DefaultChordTimeout is how long NewKeymap waits for the next key of a chord
+const DefaultChordTimeout = time.Second
+
+// keyNamesByName maps the names accepted in key bindings to keys
+var keyNamesByName = func() map[string]Key {
+	names := map[string]Key{
+		"escape":   KeyEscape,
+		"return":   KeyEnter,
+		"pagedown": KeyPageDown,
+		"pgdn":     KeyPageDown,
+		"pageup":   KeyPageUp,
+		"ins":      KeyInsert,
+		"del":      KeyDelete,
+		"bs":       KeyBackspace,
+	}
+	for key, name := range keyNames {
+		names[name] = key
+	}
+	for key := KeyF1; key <= KeyF24; key++ {
+		names[key.String()] = key
+	}
+	return names
+}()
+
+// modifiersByName maps the modifier names accepted in key bindings to modifiers
+var modifiersByName = map[string]Modifiers{
+	"ctrl":    ModCtrl,
+	"control": ModCtrl,
+	"alt":     ModAlt,
+	"shift":   ModShift,
+	"super":   ModSuper,
+	"hyper":   ModHyper,
+	"meta":    ModMeta,
+}
+
+// ParseKey parses a single key such as "ctrl+shift+a", "alt+enter", "f5",
+// "space" or "G". Names are case-insensitive except for the key of a
+// character: "G" is the same key as "shift+g"
+func ParseKey(s string) (KeyEvent, error) {
+	parts := strings.Split(s, "+")
+	// A trailing "+" is the plus key itself, as in "ctrl++"
+	if len(parts) > 1 && parts[len(parts)-1] == "" && parts[len(parts)-2] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	var ev KeyEvent
+	for _, name := range parts[:len(parts)-1] {
+		mod, ok := modifiersByName[strings.ToLower(name)]
+		if !ok {
+			return KeyEvent{}, fmt.Errorf("%w: unknown modifier %q in %q", ErrInvalidKeyBinding, name, s)
+		}
+		ev.Modifiers |= mod
+	}
+
+	name := parts[len(parts)-1]
+	if key, ok := keyNamesByName[strings.ToLower(name)]; ok {
+		ev.Key = key
+	} else if strings.EqualFold(name, "space") {
+		ev.Rune = ' '
+	} else if r, size := utf8.DecodeRuneInString(name); size == len(name) && r != utf8.RuneError && unicode.IsPrint(r) {
+		ev.Rune = r
+	} else {
+		return KeyEvent{}, fmt.Errorf("%w: unknown key %q in %q", ErrInvalidKeyBinding, name, s)
+	}
+	return normalizeKey(ev), nil
+}
+
+// ParseKeyBinding parses a sequence of keys separated by spaces, such as
+// "g g" or "ctrl+x ctrl+s"
+func ParseKeyBinding(s string) ([]KeyEvent, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty binding", ErrInvalidKeyBinding)
+	}
+	keys := make([]KeyEvent, len(fields))
+	for i, field := range fields {
+		key, err := ParseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// FormatKeyBinding renders keys the way ParseKeyBinding reads them
+func FormatKeyBinding(keys []KeyEvent) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		k = normalizeKey(k)
+		if k.Key == KeyRune && unicode.IsUpper(k.Rune) && k.Modifiers != 0 {
+			k.Rune = unicode.ToLower(k.Rune)
+			k.Modifiers |= ModShift
+		}
+		parts[i] = k.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// normalizeKey reduces a key event to the form bindings are compared in.
+// Shift on a character key is folded into the character, so Shift+a, A and
+// a kitty report of a with Shift all become A. The event type, lock state
+// and kitty extras are dropped
+func normalizeKey(k KeyEvent) KeyEvent {
+	mods := k.Modifiers &^ (ModCapsLock | ModNumLock)
+	r := k.Rune
+	if k.Key == KeyRune && mods&ModShift != 0 {
+		switch {
+		case k.ShiftedRune != 0:
+			r, mods = k.ShiftedRune, mods&^ModShift
+		case unicode.IsLower(r):
+			r, mods = unicode.ToUpper(r), mods&^ModShift
+		case unicode.IsUpper(r):
+			mods &^= ModShift
+		}
+	}
+	return KeyEvent{Key: k.Key, Rune: r, Modifiers: mods}
+}
+
+// ChordState tells what Keymap.Resolve did with a key
+type ChordState int
+
+const (
+	// ChordNone means the key is not part of any binding; handle it normally
+	ChordNone ChordState = iota
+	// ChordPending means the key started or continued a chord and was consumed
+	ChordPending
+	// ChordMatched means the key completed a binding, whose action is the
+	// last one returned
+	ChordMatched
+)
+
+// KeyBinding is a sequence of keys bound to an action in a Keymap
+type KeyBinding struct {
+	Keys   []KeyEvent
+	Action string
+}
+
+// String renders the binding's keys, e.g. "ctrl+x ctrl+s"
+func (b KeyBinding) String() string {
+	return FormatKeyBinding(b.Keys)
+}
+
+// Conflict is a pair of bindings that cannot both work as written: either
+// they have the same keys, or Binding is a prefix of Other and only runs
+// once the chord times out
+type Conflict struct {
+	Binding, Other KeyBinding
+}
+
+// String explains the conflict, e.g. `"g" (top) is a prefix of "g g" (home)`
+func (c Conflict) String() string {
+	if len(c.Binding.Keys) == len(c.Other.Keys) {
+		return fmt.Sprintf("%q is bound to both %s and %s", c.Binding.String(), c.Binding.Action, c.Other.Action)
+	}
+	return fmt.Sprintf("%q (%s) is a prefix of %q (%s)", c.Binding.String(), c.Binding.Action, c.Other.String(), c.Other.Action)
+}
+
+// Keymap resolves key events to actions. Bindings may be chords of several
+// keys; a chord is abandoned if its next key does not arrive within Timeout.
+// Like InputDecoder, a Keymap does not watch the clock itself: a program
+// should call Expire once Deadline passes
+type Keymap struct {
+	// Timeout is how long to wait for the next key of a chord
+	Timeout time.Duration
+
+	bindings []KeyBinding
+	pending  []KeyEvent
+	lastKey  time.Time
+}
+
+// NewKeymap creates an empty keymap with DefaultChordTimeout
+func NewKeymap() *Keymap {
+	return &Keymap{Timeout: DefaultChordTimeout}
+}
+
+// Bind binds keys, a string such as "ctrl+x ctrl+s", to action.
+// Binding the same keys again adds a conflicting binding; see Conflicts
+func (m *Keymap) Bind(keys, action string) error {
+	parsed, err := ParseKeyBinding(keys)
+	if err != nil {
+		return err
+	}
+	m.bindings = append(m.bindings, KeyBinding{Keys: parsed, Action: action})
+	return nil
+}
+
+// Bindings returns the bindings in the order they were added
+func (m *Keymap) Bindings() []KeyBinding {
+	return append([]KeyBinding(nil), m.bindings...)
+}
+
+// Conflicts returns every pair of bindings with the same keys and every
+// binding that is a prefix of a longer one
+func (m *Keymap) Conflicts() []Conflict {
+	var conflicts []Conflict
+	for i, a := range m.bindings {
+		for j, b := range m.bindings {
+			if i == j || len(a.Keys) > len(b.Keys) || !hasKeyPrefix(b.Keys, a.Keys) {
+				continue
+			}
+			if len(a.Keys) == len(b.Keys) && j < i {
+				// Report each pair of identical bindings once
+				continue
+			}
+			conflicts = append(conflicts, Conflict{Binding: a, Other: b})
+		}
+	}
+	return conflicts
+}
+
+// Help renders the bindings as aligned lines of keys and action
+func (m *Keymap) Help() string {
+	width := 0
+	for _, b := range m.bindings {
+		width = max(width, utf8.RuneCountInString(b.String()))
+	}
+	var sb strings.Builder
+	for _, b := range m.bindings {
+		keys := b.String()
+		sb.WriteString(keys)
+		sb.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(keys)+2))
+		sb.WriteString(b.Action)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func hasKeyPrefix(keys, prefix []KeyEvent) bool {
+	if len(prefix) > len(keys) {
+		return false
+	}
+	for i := range prefix {
+		if keys[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the action bound to exactly keys and whether any longer binding starts with keys
+func (m *Keymap) lookup(keys []KeyEvent) (action string, exact, longer bool) {
+	for _, b := range m.bindings {
+		if !hasKeyPrefix(b.Keys, keys) {
+			continue
+		}
+		if len(b.Keys) == len(keys) {
+			if !exact {
+				action, exact = b.Action, true
+			}
+		} else {
+			longer = true
+		}
+	}
+	return action, exact, longer
+}
+
+// Pending returns the keys of the chord typed so far
+func (m *Keymap) Pending() []KeyEvent {
+	return append([]KeyEvent(nil), m.pending...)
+}
+
+// Resolve feeds the next key event and returns the actions to run, in
+// order. Where one binding is a prefix of another, such as "g" and "g g",
+// the key is held as pending and the shorter binding runs from Expire. A
+// key that breaks a chord abandons it. The longest binding the keys typed
+// so far start with runs, and the keys after it are resolved again before
+// the new key; with "a" and "a b c" bound, "a b x" runs the action of "a"
+// and then resolves "b" and "x". The state tells what became of the key
+// itself. Key releases and the modifier keys themselves are ignored
+func (m *Keymap) Resolve(ev KeyEvent) ([]string, ChordState) {
+	if ev.Type == KeyRelease || ev.Key >= KeyLeftShift && ev.Key <= KeyRightMeta {
+		if len(m.pending) > 0 {
+			return nil, ChordPending
+		}
+		return nil, ChordNone
+	}
+
+	now := time.Now()
+	var actions []string
+	if len(m.pending) > 0 && now.Sub(m.lastKey) >= m.Timeout {
+		// The chord timed out without a call to Expire
+		actions = m.flush(now)
+	}
+	more, state := m.feed(normalizeKey(ev), now)
+	return append(actions, more...), state
+}
+
+// feed resolves the next normalized key
+func (m *Keymap) feed(key KeyEvent, now time.Time) ([]string, ChordState) {
+	keys := append(m.pending[:len(m.pending):len(m.pending)], key)
+	action, exact, longer := m.lookup(keys)
+	switch {
+	case longer:
+		m.pending, m.lastKey = keys, now
+		return nil, ChordPending
+	case exact:
+		m.pending = nil
+		return []string{action}, ChordMatched
+	case len(m.pending) == 0:
+		return nil, ChordNone
+	}
+
+	// The key breaks the chord. Each step leaves fewer keys pending, so
+	// the replay ends
+	actions := m.abandon(now)
+	more, state := m.feed(key, now)
+	return append(actions, more...), state
+}
+
+// abandon drops the pending chord. If the keys typed so far start with a
+// binding, it returns the action of the longest one and resolves the keys
+// after it again, which may leave a shorter chord pending
+func (m *Keymap) abandon(now time.Time) []string {
+	pending := m.pending
+	m.pending = nil
+	for n := len(pending); n > 0; n-- {
+		action, exact, _ := m.lookup(pending[:n])
+		if !exact {
+			continue
+		}
+		actions := []string{action}
+		for _, key := range pending[n:] {
+			more, _ := m.feed(key, now)
+			actions = append(actions, more...)
+		}
+		return actions
+	}
+	return nil
+}
+
+// flush abandons the pending chord and any chord its replayed keys leave
+// pending, as if no more keys were coming
+func (m *Keymap) flush(now time.Time) []string {
+	var actions []string
+	for len(m.pending) > 0 {
+		actions = append(actions, m.abandon(now)...)
+	}
+	return actions
+}
+
+// Deadline returns when the pending chord times out, and false if there is none
+func (m *Keymap) Deadline() (time.Time, bool) {
+	if len(m.pending) == 0 {
+		return time.Time{}, false
+	}
+	return m.lastKey.Add(m.Timeout), true
+}
+
+// Expire abandons the pending chord if its deadline is not after now and
+// returns the actions that run in its place, as when a key breaks the chord
+func (m *Keymap) Expire(now time.Time) []string {
+	deadline, ok := m.Deadline()
+	if !ok || now.Before(deadline) {
+		return nil
+	}
+	return m.flush(now)
+}
@@ -0,0 +1,260 @@
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestParseKey verifies parsing and rendering of single keys
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   KeyEvent
+		render string
+	}{
+		{"a", KeyEvent{Key: KeyRune, Rune: 'a'}, "a"},
+		{"G", KeyEvent{Key: KeyRune, Rune: 'G'}, "G"},
+		{"shift+g", KeyEvent{Key: KeyRune, Rune: 'G'}, "G"},
+		{"ctrl+a", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCtrl}, "ctrl+a"},
+		{"Ctrl+Shift+A", KeyEvent{Key: KeyRune, Rune: 'A', Modifiers: ModCtrl}, "ctrl+shift+a"},
+		{"alt+enter", KeyEvent{Key: KeyEnter, Modifiers: ModAlt}, "alt+enter"},
+		{"shift+F3", KeyEvent{Key: KeyF3, Modifiers: ModShift}, "shift+f3"},
+		{"ctrl+space", KeyEvent{Key: KeyRune, Rune: ' ', Modifiers: ModCtrl}, "ctrl+space"},
+		{"pgdn", KeyEvent{Key: KeyPageDown}, "pgdown"},
+		{"escape", KeyEvent{Key: KeyEscape}, "esc"},
+		{"ctrl++", KeyEvent{Key: KeyRune, Rune: '+', Modifiers: ModCtrl}, "ctrl++"},
+		{"+", KeyEvent{Key: KeyRune, Rune: '+'}, "+"},
+		{"super+ä", KeyEvent{Key: KeyRune, Rune: 'ä', Modifiers: ModSuper}, "super+ä"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseKey(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseKey(%q) = %+v, %v, want %+v, nil", tt.input, got, err, tt.want)
+			continue
+		}
+		if r := FormatKeyBinding([]KeyEvent{got}); r != tt.render {
+			t.Errorf("FormatKeyBinding(ParseKey(%q)) = %q, want %q", tt.input, r, tt.render)
+		}
+	}
+
+	for _, bad := range []string{"", "ctrl+", "hyperctrl+a", "ctrl+foo", "ab", "\x01"} {
+		if _, err := ParseKey(bad); !errors.Is(err, ErrInvalidKeyBinding) {
+			t.Errorf("ParseKey(%q) error = %v, want ErrInvalidKeyBinding", bad, err)
+		}
+	}
+}
+
+// TestKeymapResolve verifies matching of single keys and chords
+func TestKeymapResolve(t *testing.T) {
+	m := NewKeymap()
+	for keys, action := range map[string]string{
+		"ctrl+x ctrl+s": "save",
+		"ctrl+x ctrl+c": "quit",
+		"g g":           "top",
+		"G":             "bottom",
+		"ctrl+shift+a":  "select-all",
+	} {
+		if err := m.Bind(keys, action); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctrl := func(r rune) KeyEvent { return KeyEvent{Key: KeyRune, Rune: r, Modifiers: ModCtrl} }
+	key := func(r rune) KeyEvent { return KeyEvent{Key: KeyRune, Rune: r} }
+	steps := []struct {
+		ev      KeyEvent
+		actions []string
+		state   ChordState
+	}{
+		{ctrl('x'), nil, ChordPending},
+		{KeyEvent{Key: KeyRune, Rune: 'x', Modifiers: ModCtrl, Type: KeyRelease}, nil, ChordPending},
+		{ctrl('s'), []string{"save"}, ChordMatched},
+		{key('g'), nil, ChordPending},
+		{key('g'), []string{"top"}, ChordMatched},
+		{key('G'), []string{"bottom"}, ChordMatched},
+		{KeyEvent{Key: KeyRune, Rune: 'g', Modifiers: ModShift}, []string{"bottom"}, ChordMatched},
+		// A key that breaks a chord is resolved on its own
+		{key('g'), nil, ChordPending},
+		{key('G'), []string{"bottom"}, ChordMatched},
+		{key('g'), nil, ChordPending},
+		{key('x'), nil, ChordNone},
+		// modifyOtherKeys and kitty report Ctrl+Shift+A differently
+		{KeyEvent{Key: KeyRune, Rune: 'A', Modifiers: ModCtrl | ModShift}, []string{"select-all"}, ChordMatched},
+		{KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCtrl | ModShift | ModCapsLock}, []string{"select-all"}, ChordMatched},
+		{ctrl('a'), nil, ChordNone},
+	}
+	for i, s := range steps {
+		actions, state := m.Resolve(s.ev)
+		if !reflect.DeepEqual(actions, s.actions) || state != s.state {
+			t.Errorf("step %d: Resolve(%v) = %q, %v, want %q, %v", i, s.ev, actions, state, s.actions, s.state)
+		}
+	}
+}
+
+// TestKeymapBrokenPrefix verifies that a key breaking a chord runs the
+// binding of the keys typed before it
+func TestKeymapBrokenPrefix(t *testing.T) {
+	m := NewKeymap()
+	m.Bind("g", "top")
+	m.Bind("g g", "home")
+	m.Bind("j", "down")
+	m.Bind("d d", "delete-line")
+
+	key := func(r rune) KeyEvent { return KeyEvent{Key: KeyRune, Rune: r} }
+	steps := []struct {
+		ev      KeyEvent
+		actions []string
+		state   ChordState
+	}{
+		{key('g'), nil, ChordPending},
+		{key('x'), []string{"top"}, ChordNone},
+		{key('g'), nil, ChordPending},
+		{key('j'), []string{"top", "down"}, ChordMatched},
+		// The breaking key may start a chord of its own
+		{key('g'), nil, ChordPending},
+		{key('d'), []string{"top"}, ChordPending},
+		{key('d'), []string{"delete-line"}, ChordMatched},
+	}
+	for i, s := range steps {
+		actions, state := m.Resolve(s.ev)
+		if !reflect.DeepEqual(actions, s.actions) || state != s.state {
+			t.Errorf("step %d: Resolve(%v) = %q, %v, want %q, %v", i, s.ev, actions, state, s.actions, s.state)
+		}
+	}
+	if len(m.Pending()) != 0 {
+		t.Errorf("Pending() = %+v, want none", m.Pending())
+	}
+}
+
+// TestKeymapLongestPrefix verifies that a broken chord runs the longest
+// binding the pending keys start with and resolves the rest again
+func TestKeymapLongestPrefix(t *testing.T) {
+	m := NewKeymap()
+	m.Timeout = time.Hour
+	m.Bind("a", "first")
+	m.Bind("a b c", "third")
+	m.Bind("b", "second")
+	m.Bind("x y", "pair")
+
+	key := func(r rune) KeyEvent { return KeyEvent{Key: KeyRune, Rune: r} }
+	steps := []struct {
+		ev      KeyEvent
+		actions []string
+		state   ChordState
+	}{
+		{key('a'), nil, ChordPending},
+		{key('b'), nil, ChordPending},
+		{key('c'), []string{"third"}, ChordMatched},
+		// "a" runs, then "b" is resolved on its own before "x"
+		{key('a'), nil, ChordPending},
+		{key('b'), nil, ChordPending},
+		{key('x'), []string{"first", "second"}, ChordPending},
+		{key('y'), []string{"pair"}, ChordMatched},
+	}
+	for i, s := range steps {
+		actions, state := m.Resolve(s.ev)
+		if !reflect.DeepEqual(actions, s.actions) || state != s.state {
+			t.Errorf("step %d: Resolve(%v) = %q, %v, want %q, %v", i, s.ev, actions, state, s.actions, s.state)
+		}
+	}
+
+	// The same applies when the chord times out
+	m.Resolve(key('a'))
+	m.Resolve(key('b'))
+	deadline, _ := m.Deadline()
+	if actions := m.Expire(deadline); !reflect.DeepEqual(actions, []string{"first", "second"}) {
+		t.Errorf("Expire(a b) = %q, want [first second]", actions)
+	}
+	if len(m.Pending()) != 0 {
+		t.Errorf("Pending() after Expire = %+v, want none", m.Pending())
+	}
+}
+
+// TestKeymapTimeout verifies that a chord is abandoned after Timeout and a shorter binding runs from Expire
+func TestKeymapTimeout(t *testing.T) {
+	m := NewKeymap()
+	m.Timeout = time.Hour
+	m.Bind("d", "delete")
+	m.Bind("d d", "delete-line")
+
+	if _, ok := m.Deadline(); ok {
+		t.Error("Deadline() reported a deadline with no chord pending")
+	}
+	if _, state := m.Resolve(KeyEvent{Key: KeyRune, Rune: 'd'}); state != ChordPending {
+		t.Fatalf("Resolve(d) state = %v, want ChordPending", state)
+	}
+	if got := m.Pending(); !reflect.DeepEqual(got, []KeyEvent{{Key: KeyRune, Rune: 'd'}}) {
+		t.Errorf("Pending() = %+v, want [d]", got)
+	}
+	deadline, ok := m.Deadline()
+	if !ok {
+		t.Fatal("Deadline() reported no deadline with a chord pending")
+	}
+	if actions := m.Expire(deadline.Add(-time.Second)); len(actions) != 0 {
+		t.Errorf("Expire before the deadline ran %q", actions)
+	}
+	if actions := m.Expire(deadline); !reflect.DeepEqual(actions, []string{"delete"}) {
+		t.Errorf("Expire at the deadline = %q, want [delete]", actions)
+	}
+	if len(m.Pending()) != 0 {
+		t.Error("chord still pending after Expire")
+	}
+
+	m.Timeout = 0
+	m.Resolve(KeyEvent{Key: KeyRune, Rune: 'd'})
+	// With no timeout the pending "d" runs as soon as the next key arrives
+	if actions, state := m.Resolve(KeyEvent{Key: KeyRune, Rune: 'd'}); !reflect.DeepEqual(actions, []string{"delete"}) || state != ChordPending {
+		t.Errorf("Resolve(d) after the timeout = %q, %v, want [delete] and a new pending chord", actions, state)
+	}
+}
+
+// TestKeymapConflicts verifies the reporting of duplicate and shadowed bindings
+func TestKeymapConflicts(t *testing.T) {
+	m := NewKeymap()
+	m.Bind("ctrl+s", "save")
+	m.Bind("ctrl+x", "cut")
+	m.Bind("ctrl+x ctrl+s", "save-as")
+	m.Bind("ctrl+S", "search")
+	m.Bind("ctrl+s", "search")
+
+	var got []string
+	for _, c := range m.Conflicts() {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`"ctrl+s" is bound to both save and search`,
+		`"ctrl+x" (cut) is a prefix of "ctrl+x ctrl+s" (save-as)`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts() = %q, want %q", got, want)
+	}
+}
+
+func ExampleKeymap() {
+	m := NewKeymap()
+	m.Bind("ctrl+x ctrl+s", "save")
+	m.Bind("ctrl+x ctrl+c", "quit")
+	m.Bind("alt+enter", "fullscreen")
+	fmt.Print(m.Help())
+
+	for _, ev := range DecodeInput("\x18\x13\033\r") {
+		actions, state := m.Resolve(ev.(KeyEvent))
+		switch state {
+		case ChordPending:
+			fmt.Println(ev, "waiting for the next key")
+		case ChordMatched:
+			fmt.Println(ev, "runs", actions[len(actions)-1])
+		}
+	}
+	// Output:
+	// ctrl+x ctrl+s  save
+	// ctrl+x ctrl+c  quit
+	// alt+enter      fullscreen
+	// ctrl+x waiting for the next key
+	// ctrl+s runs save
+	// alt+enter runs fullscreen
+}
@@ -0,0 +1,195 @@
+package terminal_go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KittyKeyboardFlags selects the progressive enhancements of the kitty
+// keyboard protocol. See https://sw.kovidgoyal.net/kitty/keyboard-protocol/
+type KittyKeyboardFlags int
+
+const (
+	// KittyDisambiguateEscapeCodes sends keys that are ambiguous in the legacy
+	// encoding, such as Esc, Alt+key and Ctrl+I, as CSI u sequences
+	KittyDisambiguateEscapeCodes KittyKeyboardFlags = 1 << iota
+	// KittyReportEventTypes reports key repeat and release events
+	KittyReportEventTypes
+	// KittyReportAlternateKeys reports the shifted and base layout keys
+	KittyReportAlternateKeys
+	// KittyReportAllKeysAsEscapeCodes sends every key, including text keys
+	// and the modifier keys themselves, as escape sequences
+	KittyReportAllKeysAsEscapeCodes
+	// KittyReportAssociatedText includes the text a key generates. It only
+	// works together with KittyReportAllKeysAsEscapeCodes
+	KittyReportAssociatedText
+)
+
+// KittyFlagsMode tells SetKittyKeyboard how to combine flags with the current ones
+type KittyFlagsMode int
+
+const (
+	// KittyFlagsReplace sets the flags to exactly the given ones
+	KittyFlagsReplace KittyFlagsMode = 1
+	// KittyFlagsAdd sets the given flags and leaves the others unchanged
+	KittyFlagsAdd KittyFlagsMode = 2
+	// KittyFlagsRemove clears the given flags and leaves the others unchanged
+	KittyFlagsRemove KittyFlagsMode = 3
+)
+
+// QueryKittyKeyboard asks the terminal for its current kitty keyboard flags.
+// Terminals that support the protocol reply with CSI ? flags u, which
+// ParseKittyKeyboardFlags decodes; others do not reply at all
+const QueryKittyKeyboard = "\033[?u"
+
+// PushKittyKeyboard pushes flags onto the terminal's stack of kitty keyboard
+// flags, making them current. Pop them with PopKittyKeyboard before exiting
+func PushKittyKeyboard(flags KittyKeyboardFlags) string {
+	return fmt.Sprintf("\033[>%du", flags)
+}
+
+// PopKittyKeyboard pops n entries from the terminal's stack of kitty keyboard flags
+func PopKittyKeyboard(n int) string {
+	return fmt.Sprintf("\033[<%du", n)
+}
+
+// SetKittyKeyboard changes the current kitty keyboard flags without pushing
+func SetKittyKeyboard(flags KittyKeyboardFlags, mode KittyFlagsMode) string {
+	return fmt.Sprintf("\033[=%d;%du", flags, mode)
+}
+
+// ParseKittyKeyboardFlags parses the terminal's reply to QueryKittyKeyboard
+func ParseKittyKeyboardFlags(s string) (KittyKeyboardFlags, error) {
+	spec := sequenceSpec{name: "kitty keyboard flags report", typ: ActionCSIDispatch, private: '?', final: 'u', maxParams: 1}
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return KittyKeyboardFlags(a.Param(0, 0)), nil
+}
+
+// String returns the flags joined with "|", e.g. "disambiguate|report-events"
+func (f KittyKeyboardFlags) String() string {
+	names := []string{"disambiguate", "report-events", "report-alternates", "report-all-keys", "report-text"}
+	var parts []string
+	for i, name := range names {
+		if f&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	if rest := f &^ (1<<len(names) - 1); rest != 0 {
+		parts = append(parts, fmt.Sprintf("%#x", int(rest)))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return strings.Join(parts, "|")
+}
+
+// kittyFunctionalKeys maps the key codes of CSI u sequences that are not
+// Unicode characters to keys. Keypad keys are reported as the keys or
+// characters they produce
+var kittyFunctionalKeys = map[int]KeyEvent{
+	9:   {Key: KeyTab},
+	13:  {Key: KeyEnter},
+	27:  {Key: KeyEscape},
+	127: {Key: KeyBackspace},
+
+	57358: {Key: KeyCapsLock},
+	57359: {Key: KeyScrollLock},
+	57360: {Key: KeyNumLock},
+	57361: {Key: KeyPrintScreen},
+	57362: {Key: KeyPause},
+	57363: {Key: KeyMenu},
+
+	57409: {Key: KeyRune, Rune: '.'},
+	57410: {Key: KeyRune, Rune: '/'},
+	57411: {Key: KeyRune, Rune: '*'},
+	57412: {Key: KeyRune, Rune: '-'},
+	57413: {Key: KeyRune, Rune: '+'},
+	57414: {Key: KeyEnter},
+	57415: {Key: KeyRune, Rune: '='},
+	57416: {Key: KeyRune, Rune: ','},
+	57417: {Key: KeyLeft},
+	57418: {Key: KeyRight},
+	57419: {Key: KeyUp},
+	57420: {Key: KeyDown},
+	57421: {Key: KeyPageUp},
+	57422: {Key: KeyPageDown},
+	57423: {Key: KeyHome},
+	57424: {Key: KeyEnd},
+	57425: {Key: KeyInsert},
+	57426: {Key: KeyDelete},
+	57427: {Key: KeyBegin},
+
+	57441: {Key: KeyLeftShift},
+	57442: {Key: KeyLeftCtrl},
+	57443: {Key: KeyLeftAlt},
+	57444: {Key: KeyLeftSuper},
+	57445: {Key: KeyLeftHyper},
+	57446: {Key: KeyLeftMeta},
+	57447: {Key: KeyRightShift},
+	57448: {Key: KeyRightCtrl},
+	57449: {Key: KeyRightAlt},
+	57450: {Key: KeyRightSuper},
+	57451: {Key: KeyRightHyper},
+	57452: {Key: KeyRightMeta},
+}
+
+// kittyKey translates the key code of a CSI u sequence into a key event
+func kittyKey(code int) (KeyEvent, bool) {
+	if ev, ok := kittyFunctionalKeys[code]; ok {
+		return ev, true
+	}
+	switch {
+	case code >= 57376 && code <= 57387:
+		return KeyEvent{Key: KeyF13 + Key(code-57376)}, true
+	case code >= 57399 && code <= 57408:
+		return KeyEvent{Key: KeyRune, Rune: rune('0' + code - 57399)}, true
+	case code >= 0xE000 && code <= 0xF8FF:
+		// Other keys in the private use area: media keys, F25 and above
+		return KeyEvent{}, false
+	case code >= 0x20 && code <= 0x10FFFF && code != 0x7F:
+		return KeyEvent{Key: KeyRune, Rune: rune(code)}, true
+	}
+	return KeyEvent{}, false
+}
+
+// kittyKeyEvent decodes a CSI unicode-key-code:alternate-key-codes ;
+// modifiers:event-type ; text-as-codepoints u key sequence
+func kittyKeyEvent(a Action) Event {
+	if len(a.Params) == 0 || len(a.Params) > 3 {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	ev, ok := kittyKey(a.Params[0].Value(0))
+	if !ok {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	ev.ShiftedRune = rune(max(a.Params[0].Sub(0, 0), 0))
+	ev.BaseRune = rune(max(a.Params[0].Sub(1, 0), 0))
+	if len(a.Params) > 1 {
+		ev.Modifiers = kittyModifiers(a.Params[1].Value(1))
+		ev.Type = kittyEventType(a.Params[1])
+	}
+	if len(a.Params) > 2 {
+		var text strings.Builder
+		for _, c := range a.Params[2] {
+			if c > 0 {
+				text.WriteRune(rune(c))
+			}
+		}
+		ev.Text = text.String()
+	}
+	return ev
+}
+
+// kittyEventType reads the event type sub-parameter of a modifiers parameter
+func kittyEventType(p Param) KeyEventType {
+	switch p.Sub(0, 1) {
+	case 2:
+		return KeyRepeat
+	case 3:
+		return KeyRelease
+	}
+	return KeyPress
+}
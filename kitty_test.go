@@ -0,0 +1,107 @@
+package terminal_go
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestKittyKeyboardEmitters verifies the sequences that change the kitty keyboard flags
+func TestKittyKeyboardEmitters(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{PushKittyKeyboard(KittyDisambiguateEscapeCodes | KittyReportEventTypes), "\033[>3u"},
+		{PopKittyKeyboard(1), "\033[<1u"},
+		{SetKittyKeyboard(KittyReportAlternateKeys, KittyFlagsAdd), "\033[=4;2u"},
+		{SetKittyKeyboard(31, KittyFlagsReplace), "\033[=31;1u"},
+		{QueryKittyKeyboard, "\033[?u"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+// TestParseKittyKeyboardFlags verifies decoding of the reply to QueryKittyKeyboard
+func TestParseKittyKeyboardFlags(t *testing.T) {
+	flags, err := ParseKittyKeyboardFlags("\033[?13u")
+	want := KittyDisambiguateEscapeCodes | KittyReportAlternateKeys | KittyReportAllKeysAsEscapeCodes
+	if err != nil || flags != want {
+		t.Errorf("ParseKittyKeyboardFlags = %v, %v, want %v, nil", flags, err, want)
+	}
+	if _, err := ParseKittyKeyboardFlags("\033[>13u"); err == nil {
+		t.Error("ParseKittyKeyboardFlags accepted a push sequence")
+	}
+}
+
+// TestDecodeInputKitty verifies decoding of kitty keyboard protocol key events
+func TestDecodeInputKitty(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Event
+	}{
+		{"\033[105;5u", KeyEvent{Key: KeyRune, Rune: 'i', Modifiers: ModCtrl}},
+		{"\033[9u", KeyEvent{Key: KeyTab}},
+		{"\033[27u", KeyEvent{Key: KeyEscape}},
+		{"\033[13;3u", KeyEvent{Key: KeyEnter, Modifiers: ModAlt}},
+		{"\033[97;1:3u", KeyEvent{Key: KeyRune, Rune: 'a', Type: KeyRelease}},
+		{"\033[97;1:2u", KeyEvent{Key: KeyRune, Rune: 'a', Type: KeyRepeat}},
+		{"\033[97:65;2u", KeyEvent{Key: KeyRune, Rune: 'a', ShiftedRune: 'A', Modifiers: ModShift}},
+		{"\033[1089::99;5u", KeyEvent{Key: KeyRune, Rune: 'с', BaseRune: 'c', Modifiers: ModCtrl}},
+		{"\033[97;2;65u", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModShift, Text: "A"}},
+		{"\033[97;;97:98u", KeyEvent{Key: KeyRune, Rune: 'a', Text: "ab"}},
+		{"\033[115;9u", KeyEvent{Key: KeyRune, Rune: 's', Modifiers: ModSuper}},
+		{"\033[97;65u", KeyEvent{Key: KeyRune, Rune: 'a', Modifiers: ModCapsLock}},
+		{"\033[57376u", KeyEvent{Key: KeyF13}},
+		{"\033[57399u", KeyEvent{Key: KeyRune, Rune: '0'}},
+		{"\033[57414u", KeyEvent{Key: KeyEnter}},
+		{"\033[57441;2u", KeyEvent{Key: KeyLeftShift, Modifiers: ModShift}},
+		{"\033[1;5:3A", KeyEvent{Key: KeyUp, Modifiers: ModCtrl, Type: KeyRelease}},
+		{"\033[1;9:1A", KeyEvent{Key: KeyUp, Modifiers: ModSuper}},
+		{"\033[3;1:2~", KeyEvent{Key: KeyDelete, Type: KeyRepeat}},
+		{"\033[57428u", UnknownEvent{Raw: "\033[57428u"}},
+		{"\033[?1u", UnknownEvent{Raw: "\033[?1u"}},
+	}
+
+	for _, tt := range tests {
+		got := DecodeInput(tt.input)
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("DecodeInput(%q) = %+v, want [%+v]", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestDescribeKittyKeyboard verifies the explanations of kitty keyboard sequences
+func TestDescribeKittyKeyboard(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{PushKittyKeyboard(KittyDisambiguateEscapeCodes | KittyReportEventTypes), "push kitty keyboard flags disambiguate|report-events"},
+		{PopKittyKeyboard(2), "pop 2 kitty keyboard flag entries"},
+		{SetKittyKeyboard(KittyReportAssociatedText, KittyFlagsRemove), "remove kitty keyboard flags report-text"},
+		{QueryKittyKeyboard, "query kitty keyboard flags"},
+	}
+
+	for _, tt := range tests {
+		if got := Describe(tt.seq).Summary; got != tt.want {
+			t.Errorf("Describe(%q).Summary = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
+
+func ExamplePushKittyKeyboard() {
+	fmt.Printf("%q\n", PushKittyKeyboard(KittyDisambiguateEscapeCodes|KittyReportEventTypes))
+	for _, ev := range DecodeInput("\033[105;5u\033[9u\033[105;5:3u") {
+		k := ev.(KeyEvent)
+		fmt.Println(k, k.Type)
+	}
+	fmt.Printf("%q\n", PopKittyKeyboard(1))
+	// Output:
+	// "\x1b[>3u"
+	// ctrl+i press
+	// tab press
+	// ctrl+i release
+	// "\x1b[<1u"
+}
@@ -0,0 +1,117 @@
+package terminal_go
+
+import "fmt"
+
+// ModeState is a terminal's answer to a mode request, as reported by DECRPM
+type ModeState int
+
+const (
+	// ModeNotRecognized means the terminal does not know the mode
+	ModeNotRecognized ModeState = 0
+	// ModeSet means the mode is enabled
+	ModeSet ModeState = 1
+	// ModeReset means the mode is disabled
+	ModeReset ModeState = 2
+	// ModePermanentlySet means the mode is enabled and cannot be disabled
+	ModePermanentlySet ModeState = 3
+	// ModePermanentlyReset means the mode is disabled and cannot be enabled
+	ModePermanentlyReset ModeState = 4
+)
+
+var modeStateNames = [...]string{
+	ModeNotRecognized:    "not recognized",
+	ModeSet:              "set",
+	ModeReset:            "reset",
+	ModePermanentlySet:   "permanently set",
+	ModePermanentlyReset: "permanently reset",
+}
+
+// String returns a description of the state, e.g. "permanently set"
+func (s ModeState) String() string {
+	if s >= 0 && int(s) < len(modeStateNames) {
+		return modeStateNames[s]
+	}
+	return fmt.Sprintf("ModeState(%d)", int(s))
+}
+
+// IsSet reports whether the mode is enabled, permanently or not
+func (s ModeState) IsSet() bool {
+	return s == ModeSet || s == ModePermanentlySet
+}
+
+// IsSupported reports whether the mode can be changed, so that enabling it
+// with SetMode or DECSET has an effect
+func (s ModeState) IsSupported() bool {
+	return s == ModeSet || s == ModeReset
+}
+
+// RequestMode (DECRQM) asks for the state of an ANSI mode, numbered as for
+// SetMode and ResetMode. ParseModeReport decodes the reply
+func RequestMode(mode int) string {
+	return fmt.Sprintf("\033[%d$p", mode)
+}
+
+// RequestPrivateMode (DECRQM) asks for the state of a DEC private mode, such
+// as 2004 for bracketed paste or 2026 for synchronized output.
+// ParsePrivateModeReport decodes the reply
+func RequestPrivateMode(mode int) string {
+	return fmt.Sprintf("\033[?%d$p", mode)
+}
+
+// ReportMode (DECRPM) formats the reply to RequestMode
+func ReportMode(mode int, state ModeState) string {
+	return fmt.Sprintf("\033[%d;%d$y", mode, state)
+}
+
+// ReportPrivateMode (DECRPM) formats the reply to RequestPrivateMode
+func ReportPrivateMode(mode int, state ModeState) string {
+	return fmt.Sprintf("\033[?%d;%d$y", mode, state)
+}
+
+// parseModeRequest parses a DECRQM sequence with the given private marker
+func parseModeRequest(s, name string, private byte) (int, error) {
+	spec := sequenceSpec{name: name, typ: ActionCSIDispatch, private: private, intermediates: "$", final: 'p', maxParams: 1}
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, err
+	}
+	mode := a.Param(0, OmittedParam)
+	if mode == OmittedParam {
+		return 0, spec.error(s)
+	}
+	return mode, nil
+}
+
+// ParseRequestMode parses a sequence produced by RequestMode
+func ParseRequestMode(s string) (mode int, err error) {
+	return parseModeRequest(s, "RequestMode", 0)
+}
+
+// ParseRequestPrivateMode parses a sequence produced by RequestPrivateMode
+func ParseRequestPrivateMode(s string) (mode int, err error) {
+	return parseModeRequest(s, "RequestPrivateMode", '?')
+}
+
+// parseModeReport parses a DECRPM sequence with the given private marker
+func parseModeReport(s, name string, private byte) (int, ModeState, error) {
+	spec := sequenceSpec{name: name, typ: ActionCSIDispatch, private: private, intermediates: "$", final: 'y', maxParams: 2}
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	mode, state := a.Param(0, OmittedParam), a.Param(1, OmittedParam)
+	if mode == OmittedParam || state < 0 || state >= len(modeStateNames) {
+		return 0, 0, spec.error(s)
+	}
+	return mode, ModeState(state), nil
+}
+
+// ParseModeReport parses the reply to RequestMode
+func ParseModeReport(s string) (mode int, state ModeState, err error) {
+	return parseModeReport(s, "ModeReport", 0)
+}
+
+// ParsePrivateModeReport parses the reply to RequestPrivateMode
+func ParsePrivateModeReport(s string) (mode int, state ModeState, err error) {
+	return parseModeReport(s, "PrivateModeReport", '?')
+}
@@ -0,0 +1,87 @@
+package terminal_go
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestModeRequests verifies the DECRQM and DECRPM emitters and their parsers
+func TestModeRequests(t *testing.T) {
+	if got, want := RequestMode(4), "\033[4$p"; got != want {
+		t.Errorf("RequestMode(4) = %q, want %q", got, want)
+	}
+	if got, want := RequestPrivateMode(2026), "\033[?2026$p"; got != want {
+		t.Errorf("RequestPrivateMode(2026) = %q, want %q", got, want)
+	}
+	if mode, err := ParseRequestMode(RequestMode(20)); err != nil || mode != 20 {
+		t.Errorf("ParseRequestMode() = %d, %v, want 20, nil", mode, err)
+	}
+	if mode, err := ParseRequestPrivateMode(RequestPrivateMode(2004)); err != nil || mode != 2004 {
+		t.Errorf("ParseRequestPrivateMode() = %d, %v, want 2004, nil", mode, err)
+	}
+	for _, s := range []string{RequestPrivateMode(1), "\033[$p", SetMode(4), SoftTerminalReset()} {
+		if _, err := ParseRequestMode(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseRequestMode(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestParseModeReport verifies decoding of DECRPM replies
+func TestParseModeReport(t *testing.T) {
+	for state := ModeNotRecognized; state <= ModePermanentlyReset; state++ {
+		mode, got, err := ParsePrivateModeReport(ReportPrivateMode(2026, state))
+		if err != nil || mode != 2026 || got != state {
+			t.Errorf("ParsePrivateModeReport(%q) = %d, %v, %v, want 2026, %v, nil", ReportPrivateMode(2026, state), mode, got, err, state)
+		}
+		mode, got, err = ParseModeReport(ReportMode(4, state))
+		if err != nil || mode != 4 || got != state {
+			t.Errorf("ParseModeReport(%q) = %d, %v, %v, want 4, %v, nil", ReportMode(4, state), mode, got, err, state)
+		}
+	}
+	for _, s := range []string{ReportMode(4, ModeSet), "\033[?2026;5$y", "\033[?2026$y", "\033[?;1$y", RequestPrivateMode(2026)} {
+		if _, _, err := ParsePrivateModeReport(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParsePrivateModeReport(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+}
+
+// TestModeState verifies the ModeState helpers
+func TestModeState(t *testing.T) {
+	tests := []struct {
+		state          ModeState
+		name           string
+		set, supported bool
+	}{
+		{ModeNotRecognized, "not recognized", false, false},
+		{ModeSet, "set", true, true},
+		{ModeReset, "reset", false, true},
+		{ModePermanentlySet, "permanently set", true, false},
+		{ModePermanentlyReset, "permanently reset", false, false},
+		{ModeState(9), "ModeState(9)", false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.name {
+			t.Errorf("ModeState(%d).String() = %q, want %q", tt.state, got, tt.name)
+		}
+		if tt.state.IsSet() != tt.set || tt.state.IsSupported() != tt.supported {
+			t.Errorf("%v: IsSet() = %v, IsSupported() = %v", tt.state, tt.state.IsSet(), tt.state.IsSupported())
+		}
+	}
+}
+
+// TestDescribeModeRequests verifies explanations of DECRQM and DECRPM
+func TestDescribeModeRequests(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{RequestMode(4), "DECRQM — request state of mode 4 (insert mode)"},
+		{RequestPrivateMode(2026), "DECRQM — request state of DEC private mode 2026 (synchronized output)"},
+		{ReportPrivateMode(2004, ModeSet), "DECRPM — DEC private mode 2004 (bracketed paste) is set"},
+		{ReportMode(99, ModeNotRecognized), "DECRPM — mode 99 is not recognized"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
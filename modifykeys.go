@@ -0,0 +1,59 @@
+package terminal_go
+
+import "fmt"
+
+// ModifyOtherKeysLevel selects how xterm's modifyOtherKeys resource reports
+// keys pressed with modifiers. It is the fallback for terminals without the
+// kitty keyboard protocol
+type ModifyOtherKeysLevel int
+
+const (
+	// ModifyOtherKeysOff sends the legacy encoding, e.g. Ctrl+Shift+A as Ctrl+A
+	ModifyOtherKeysOff ModifyOtherKeysLevel = 0
+	// ModifyOtherKeysExceptWellKnown reports modified keys as CSI 27 ; mod ;
+	// code ~ except combinations with well-known legacy meanings such as Ctrl+A
+	ModifyOtherKeysExceptWellKnown ModifyOtherKeysLevel = 1
+	// ModifyOtherKeysAll reports every modified key as CSI 27 ; mod ; code ~,
+	// including Ctrl+Enter and Ctrl+I
+	ModifyOtherKeysAll ModifyOtherKeysLevel = 2
+)
+
+// ResetModifyOtherKeys (XTMODKEYS) restores modifyOtherKeys to the terminal's initial setting
+const ResetModifyOtherKeys = "\033[>4m"
+
+// QueryModifyOtherKeys (XTQMODKEYS) asks the terminal for its modifyOtherKeys level.
+// Terminals that support it reply with CSI > 4 ; level m, which
+// ParseModifyOtherKeys decodes
+const QueryModifyOtherKeys = "\033[?4m"
+
+// SetModifyOtherKeys (XTMODKEYS) sets the modifyOtherKeys level
+func SetModifyOtherKeys(level ModifyOtherKeysLevel) string {
+	return fmt.Sprintf("\033[>4;%dm", level)
+}
+
+// ParseModifyOtherKeys parses a sequence produced by SetModifyOtherKeys,
+// which is also the terminal's reply to QueryModifyOtherKeys
+func ParseModifyOtherKeys(s string) (ModifyOtherKeysLevel, error) {
+	spec := sequenceSpec{name: "XTMODKEYS", typ: ActionCSIDispatch, private: '>', final: 'm', maxParams: 2}
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(a.Params) != 2 || a.Param(0, 0) != 4 {
+		return 0, spec.error(s)
+	}
+	return ModifyOtherKeysLevel(a.Param(1, 0)), nil
+}
+
+// modifyOtherKeysEvent decodes a CSI 27 ; modifiers ; code ~ key sequence
+func modifyOtherKeysEvent(a Action) Event {
+	if len(a.Params) != 3 {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	ev, ok := kittyKey(a.Param(2, 0))
+	if !ok {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	ev.Modifiers = xtermModifiers(a.Param(1, 1))
+	return ev
+}
@@ -0,0 +1,60 @@
+package terminal_go
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestModifyOtherKeys verifies the XTMODKEYS emitters and their parser
+func TestModifyOtherKeys(t *testing.T) {
+	for _, level := range []ModifyOtherKeysLevel{ModifyOtherKeysOff, ModifyOtherKeysExceptWellKnown, ModifyOtherKeysAll} {
+		seq := SetModifyOtherKeys(level)
+		if want := fmt.Sprintf("\033[>4;%dm", level); seq != want {
+			t.Errorf("SetModifyOtherKeys(%d) = %q, want %q", level, seq, want)
+		}
+		got, err := ParseModifyOtherKeys(seq)
+		if err != nil || got != level {
+			t.Errorf("ParseModifyOtherKeys(%q) = %d, %v, want %d, nil", seq, got, err, level)
+		}
+	}
+	for _, s := range []string{ResetModifyOtherKeys, QueryModifyOtherKeys, "\033[>1;2m"} {
+		if _, err := ParseModifyOtherKeys(s); err == nil {
+			t.Errorf("ParseModifyOtherKeys(%q) succeeded", s)
+		}
+	}
+
+	tests := []struct {
+		seq, want string
+	}{
+		{SetModifyOtherKeys(ModifyOtherKeysAll), "XTMODKEYS — set modifyOtherKeys level 2"},
+		{ResetModifyOtherKeys, "XTMODKEYS — reset modifyOtherKeys (ResetModifyOtherKeys)"},
+		{QueryModifyOtherKeys, "XTQMODKEYS — query modifyOtherKeys level (QueryModifyOtherKeys)"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
+
+// TestDecodeInputModifyOtherKeys verifies decoding of CSI 27 ; mod ; code ~ key sequences
+func TestDecodeInputModifyOtherKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Event
+	}{
+		{"\033[27;5;13~", KeyEvent{Key: KeyEnter, Modifiers: ModCtrl}},
+		{"\033[27;6;65~", KeyEvent{Key: KeyRune, Rune: 'A', Modifiers: ModCtrl | ModShift}},
+		{"\033[27;5;105~", KeyEvent{Key: KeyRune, Rune: 'i', Modifiers: ModCtrl}},
+		{"\033[27;2;9~", KeyEvent{Key: KeyTab, Modifiers: ModShift}},
+		{"\033[27;3;127~", KeyEvent{Key: KeyBackspace, Modifiers: ModAlt}},
+		{"\033[27;5~", UnknownEvent{Raw: "\033[27;5~"}},
+	}
+
+	for _, tt := range tests {
+		got := DecodeInput(tt.input)
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("DecodeInput(%q) = %+v, want [%+v]", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package terminal_go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MouseMode is a DEC private mode that turns on mouse reporting or selects
+// how reports are encoded. Combine one tracking mode with one encoding
+type MouseMode int
+
+const (
+	// MouseX10 reports button presses only
+	MouseX10 MouseMode = 9
+	// MouseNormal reports button presses and releases
+	MouseNormal MouseMode = 1000
+	// MouseButtonEvent also reports motion while a button is held down
+	MouseButtonEvent MouseMode = 1002
+	// MouseAnyEvent reports all motion, even with no button held down
+	MouseAnyEvent MouseMode = 1003
+
+	// MouseSGR encodes reports as CSI < b ; x ; y M or m, without the
+	// coordinate limit of the legacy encoding and with the released button
+	MouseSGR MouseMode = 1006
+	// MouseURXVT encodes reports as CSI b ; x ; y M with decimal coordinates
+	MouseURXVT MouseMode = 1015
+	// MouseSGRPixel is MouseSGR with coordinates in pixels instead of cells
+	MouseSGRPixel MouseMode = 1016
+)
+
+// EnableMouse turns on the given mouse modes, e.g.
+// EnableMouse(MouseButtonEvent, MouseSGR)
+func EnableMouse(modes ...MouseMode) string {
+	return mouseModes(modes, 'h')
+}
+
+// DisableMouse turns off the given mouse modes
+func DisableMouse(modes ...MouseMode) string {
+	return mouseModes(modes, 'l')
+}
+
+func mouseModes(modes []MouseMode, final byte) string {
+	nums := make([]string, len(modes))
+	for i, m := range modes {
+		nums[i] = strconv.Itoa(int(m))
+	}
+	return "\033[?" + strings.Join(nums, ";") + string(final)
+}
+
+// MouseButton identifies the mouse button or wheel direction of a MouseEvent
+type MouseButton int
+
+const (
+	// MouseNone is reported for motion with no button held down and for
+	// releases in the legacy encodings, which do not tell which button
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+	// MouseButton8 to MouseButton11 are the extra buttons; 8 and 9 are
+	// usually back and forward
+	MouseButton8
+	MouseButton9
+	MouseButton10
+	MouseButton11
+)
+
+var mouseButtonNames = [...]string{"none", "left", "middle", "right", "wheelup", "wheeldown",
+	"wheelleft", "wheelright", "button8", "button9", "button10", "button11"}
+
+// String returns the lower-case name of the button, e.g. "left" or "wheelup"
+func (b MouseButton) String() string {
+	if b >= 0 && int(b) < len(mouseButtonNames) {
+		return mouseButtonNames[b]
+	}
+	return "MouseButton(" + strconv.Itoa(int(b)) + ")"
+}
+
+// MouseAction tells what happened to the button of a MouseEvent
+type MouseAction int
+
+const (
+	// MousePress is a button press or a wheel step
+	MousePress MouseAction = iota
+	// MouseRelease is a button release
+	MouseRelease
+	// MouseMotion is the pointer moving, with Button held down if it is not MouseNone
+	MouseMotion
+)
+
+// String returns "press", "release" or "motion"
+func (a MouseAction) String() string {
+	switch a {
+	case MousePress:
+		return "press"
+	case MouseRelease:
+		return "release"
+	case MouseMotion:
+		return "motion"
+	}
+	return "MouseAction(" + strconv.Itoa(int(a)) + ")"
+}
+
+// MouseEvent is a mouse report decoded from terminal input
+type MouseEvent struct {
+	// X and Y are the 1-based column and row of the pointer, or its pixel
+	// position with MouseSGRPixel
+	X, Y int
+	// Button is the button pressed, released or held during motion
+	Button MouseButton
+	// Action tells what happened
+	Action MouseAction
+	// Modifiers holds Shift, Alt and Ctrl. Terminals often use these
+	// combinations themselves, e.g. Shift to select text
+	Modifiers Modifiers
+}
+
+func (MouseEvent) isEvent() {}
+
+// String renders the event, e.g. "ctrl+left press at 10,5"
+func (m MouseEvent) String() string {
+	name := m.Button.String()
+	if m.Modifiers != 0 {
+		name = m.Modifiers.String() + "+" + name
+	}
+	return fmt.Sprintf("%s %s at %d,%d", name, m.Action, m.X, m.Y)
+}
+
+// decodeMouseButton decodes the button byte shared by all mouse encodings.
+// In the legacy encodings a release has button bits 3
+func decodeMouseButton(cb int) (MouseButton, MouseAction, Modifiers) {
+	var mods Modifiers
+	if cb&4 != 0 {
+		mods |= ModShift
+	}
+	if cb&8 != 0 {
+		mods |= ModAlt
+	}
+	if cb&16 != 0 {
+		mods |= ModCtrl
+	}
+	action := MousePress
+	if cb&32 != 0 {
+		action = MouseMotion
+	}
+
+	low := MouseButton(cb & 3)
+	var button MouseButton
+	switch {
+	case cb&128 != 0:
+		button = MouseButton8 + low
+	case cb&64 != 0:
+		button = MouseWheelUp + low
+	case low == 3:
+		button = MouseNone
+		if action == MousePress {
+			action = MouseRelease
+		}
+	default:
+		button = MouseLeft + low
+	}
+	return button, action, mods
+}
+
+// legacyMouseEvent decodes a CSI M Cb Cx Cy report, in which each value is
+// a single byte offset by 32
+func legacyMouseEvent(seq []byte) MouseEvent {
+	button, action, mods := decodeMouseButton(int(seq[3]) - 32)
+	return MouseEvent{X: int(seq[4]) - 32, Y: int(seq[5]) - 32, Button: button, Action: action, Modifiers: mods}
+}
+
+// sgrMouseEvent decodes a CSI < b ; x ; y M or m report (SGR and SGR-pixel)
+// and a CSI b ; x ; y M report (URXVT)
+func sgrMouseEvent(a Action) Event {
+	if len(a.Params) != 3 {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	cb := a.Param(0, 0)
+	if a.Private == 0 {
+		cb -= 32
+	}
+	if cb < 0 {
+		return UnknownEvent{Raw: a.Raw}
+	}
+	button, action, mods := decodeMouseButton(cb)
+	if a.Final == 'm' {
+		action = MouseRelease
+	}
+	return MouseEvent{X: a.Param(1, 1), Y: a.Param(2, 1), Button: button, Action: action, Modifiers: mods}
+}
@@ -0,0 +1,94 @@
+package terminal_go
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestMouseModes verifies the sequences that turn mouse reporting on and off
+func TestMouseModes(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{EnableMouse(MouseX10), "\033[?9h"},
+		{EnableMouse(MouseNormal, MouseSGR), "\033[?1000;1006h"},
+		{EnableMouse(MouseButtonEvent, MouseURXVT), "\033[?1002;1015h"},
+		{EnableMouse(MouseAnyEvent, MouseSGRPixel), "\033[?1003;1016h"},
+		{DisableMouse(MouseAnyEvent, MouseSGR), "\033[?1003;1006l"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	if got := Describe(EnableMouse(MouseButtonEvent, MouseSGR)).Summary; got != "enable mouse button-event tracking, enable SGR mouse encoding" {
+		t.Errorf("Describe(EnableMouse(...)).Summary = %q", got)
+	}
+}
+
+// TestDecodeInputMouse verifies decoding of legacy, URXVT and SGR mouse reports
+func TestDecodeInputMouse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  MouseEvent
+	}{
+		{"\033[M !!", MouseEvent{X: 1, Y: 1, Button: MouseLeft, Action: MousePress}},
+		{"\033[M\"+%", MouseEvent{X: 11, Y: 5, Button: MouseRight, Action: MousePress}},
+		{"\033[M#+%", MouseEvent{X: 11, Y: 5, Button: MouseNone, Action: MouseRelease}},
+		{"\033[M0+%", MouseEvent{X: 11, Y: 5, Button: MouseLeft, Action: MousePress, Modifiers: ModCtrl}},
+		{"\033[M@+%", MouseEvent{X: 11, Y: 5, Button: MouseLeft, Action: MouseMotion}},
+		{"\033[MC+%", MouseEvent{X: 11, Y: 5, Button: MouseNone, Action: MouseMotion}},
+		{"\033[M`+%", MouseEvent{X: 11, Y: 5, Button: MouseWheelUp, Action: MousePress}},
+		{"\033[M\xff\xff\xff", MouseEvent{X: 223, Y: 223, Button: MouseButton11, Action: MousePress,
+			Modifiers: ModShift | ModAlt | ModCtrl}},
+		{"\033[32;11;5M", MouseEvent{X: 11, Y: 5, Button: MouseLeft, Action: MousePress}},
+		{"\033[35;300;200M", MouseEvent{X: 300, Y: 200, Button: MouseNone, Action: MouseRelease}},
+		{"\033[<0;11;5M", MouseEvent{X: 11, Y: 5, Button: MouseLeft, Action: MousePress}},
+		{"\033[<0;11;5m", MouseEvent{X: 11, Y: 5, Button: MouseLeft, Action: MouseRelease}},
+		{"\033[<1;500;300M", MouseEvent{X: 500, Y: 300, Button: MouseMiddle, Action: MousePress}},
+		{"\033[<34;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseRight, Action: MouseMotion}},
+		{"\033[<35;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseNone, Action: MouseMotion}},
+		{"\033[<64;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseWheelUp, Action: MousePress}},
+		{"\033[<65;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseWheelDown, Action: MousePress}},
+		{"\033[<66;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseWheelLeft, Action: MousePress}},
+		{"\033[<71;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseWheelRight, Action: MousePress, Modifiers: ModShift}},
+		{"\033[<128;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseButton8, Action: MousePress}},
+		{"\033[<129;2;3m", MouseEvent{X: 2, Y: 3, Button: MouseButton9, Action: MouseRelease}},
+		{"\033[<131;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseButton11, Action: MousePress}},
+		{"\033[<24;2;3M", MouseEvent{X: 2, Y: 3, Button: MouseLeft, Action: MousePress, Modifiers: ModAlt | ModCtrl}},
+	}
+
+	for _, tt := range tests {
+		got := DecodeInput(tt.input)
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("DecodeInput(%q) = %+v, want [%+v]", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestDecodeInputMouseSplit verifies that a legacy report split across reads is held back until complete
+func TestDecodeInputMouseSplit(t *testing.T) {
+	d := NewInputDecoder()
+	if got := d.Feed([]byte("\033[M ")); len(got) != 0 {
+		t.Fatalf("Feed of a partial report returned %+v", got)
+	}
+	got := d.Feed([]byte("!!a"))
+	if len(got) != 2 || got[0] != (MouseEvent{X: 1, Y: 1, Button: MouseLeft}) || got[1] != (KeyEvent{Key: KeyRune, Rune: 'a'}) {
+		t.Errorf("Feed of the rest = %+v, want the press and the a key", got)
+	}
+}
+
+func ExampleEnableMouse() {
+	fmt.Printf("%q\n", EnableMouse(MouseButtonEvent, MouseSGR))
+	for _, ev := range DecodeInput("\033[<0;10;5M\033[<32;12;5M\033[<0;12;5m\033[<65;12;5M") {
+		fmt.Println(ev)
+	}
+	// Output:
+	// "\x1b[?1002;1006h"
+	// left press at 10,5
+	// left motion at 12,5
+	// left release at 12,5
+	// wheeldown press at 12,5
+}
@@ -0,0 +1,337 @@
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnexpectedSequence is returned when a string is not the sequence a parse function expects
+var ErrUnexpectedSequence = errors.New("terminal_go: unexpected sequence")
+
+// sequenceSpec describes the shape of a single ESC, CSI or DCS sequence
+type sequenceSpec struct {
+	name          string
+	typ           ActionType
+	private       byte
+	intermediates string
+	final         byte
+	maxParams     int
+}
+
+// parse checks that s consists of exactly one sequence matching the spec and returns it
+func (spec sequenceSpec) parse(s string) (Action, error) {
+	actions := ParseString(s)
+	if spec.typ == ActionDCSHook {
+		// The parser splits a device control string into several actions,
+		// which the decoder joins into the hook action with its payload
+		actions = nil
+		for _, tok := range DecodeString(s) {
+			actions = append(actions, tok.Action)
+		}
+	}
+	if len(actions) != 1 {
+		return Action{}, spec.error(s)
+	}
+	a := actions[0]
+	if a.Type != spec.typ || a.Private != spec.private || a.Intermediates != spec.intermediates ||
+		(spec.final != 0 && a.Final != spec.final) || len(a.Params) > spec.maxParams {
+		return Action{}, spec.error(s)
+	}
+	for _, p := range a.Params {
+		if len(p) > 1 {
+			return Action{}, spec.error(s)
+		}
+	}
+	return a, nil
+}
+
+func (spec sequenceSpec) error(s string) error {
+	return fmt.Errorf("%w: %q is not a %s sequence", ErrUnexpectedSequence, s, spec.name)
+}
+
+func csiSpec(name string, final byte, maxParams int) sequenceSpec {
+	return sequenceSpec{name: name, typ: ActionCSIDispatch, final: final, maxParams: maxParams}
+}
+
+// parseCount parses a CSI sequence with a single parameter that defaults to def
+func parseCount(s, name string, final byte, def int) (int, error) {
+	a, err := csiSpec(name, final, 1).parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return a.Param(0, def), nil
+}
+
+// ParseCursorPosition parses a sequence produced by CursorPosition.
+// Omitted coordinates default to 1
+func ParseCursorPosition(s string) (row, column int, err error) {
+	a, err := csiSpec("CursorPosition", 'H', 2).parse(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Param(0, 1), a.Param(1, 1), nil
+}
+
+// ParseCursorForward parses a sequence produced by CursorForward
+func ParseCursorForward(s string) (columns int, err error) {
+	return parseCount(s, "CursorForward", 'C', 1)
+}
+
+// ParseCursorBackward parses a sequence produced by CursorBackward
+func ParseCursorBackward(s string) (columns int, err error) {
+	return parseCount(s, "CursorBackward", 'D', 1)
+}
+
+// ParseCursorDown parses a sequence produced by CursorDown
+func ParseCursorDown(s string) (lines int, err error) {
+	return parseCount(s, "CursorDown", 'B', 1)
+}
+
+// ParseCursorUp parses a sequence produced by CursorUp
+func ParseCursorUp(s string) (lines int, err error) {
+	return parseCount(s, "CursorUp", 'A', 1)
+}
+
+// ParseCursorNextLine parses a sequence produced by CursorNextLine
+func ParseCursorNextLine(s string) (lines int, err error) {
+	return parseCount(s, "CursorNextLine", 'E', 1)
+}
+
+// ParseCursorPreviousLine parses a sequence produced by CursorPreviousLine
+func ParseCursorPreviousLine(s string) (lines int, err error) {
+	return parseCount(s, "CursorPreviousLine", 'F', 1)
+}
+
+// ParseCursorHorizontalAbsolute parses a sequence produced by CursorHorizontalAbsolute
+func ParseCursorHorizontalAbsolute(s string) (column int, err error) {
+	return parseCount(s, "CursorHorizontalAbsolute", 'G', 1)
+}
+
+// parseColor parses an SGR sequence of the form selector;mode;values...
+func parseColor(s, name string, selector, mode int, n int) ([]int, error) {
+	spec := csiSpec(name, 'm', n+2)
+	a, err := spec.parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.Params) != n+2 || a.Param(0, 0) != selector || a.Param(1, 0) != mode {
+		return nil, spec.error(s)
+	}
+	values := make([]int, n)
+	for i := range values {
+		values[i] = a.Param(i+2, 0)
+	}
+	return values, nil
+}
+
+// ParseSetTextColor parses a sequence produced by SetTextColor
+func ParseSetTextColor(s string) (color int, err error) {
+	values, err := parseColor(s, "SetTextColor", 38, 5, 1)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// ParseSetBackgroundColor parses a sequence produced by SetBackgroundColor
+func ParseSetBackgroundColor(s string) (color int, err error) {
+	values, err := parseColor(s, "SetBackgroundColor", 48, 5, 1)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// ParseSetRGBTextColor parses a sequence produced by SetRGBTextColor
+func ParseSetRGBTextColor(s string) (r, g, b int, err error) {
+	values, err := parseColor(s, "SetRGBTextColor", 38, 2, 3)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// ParseSetRGBBackgroundColor parses a sequence produced by SetRGBBackgroundColor
+func ParseSetRGBBackgroundColor(s string) (r, g, b int, err error) {
+	values, err := parseColor(s, "SetRGBBackgroundColor", 48, 2, 3)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return values[0], values[1], values[2], nil
+}
+
+// ParseScrollUpLines parses a sequence produced by ScrollUpLines or the ScrollUp constant
+func ParseScrollUpLines(s string) (lines int, err error) {
+	return parseCount(s, "ScrollUpLines", 'S', 1)
+}
+
+// ParseScrollDownLines parses a sequence produced by ScrollDownLines or the ScrollDown constant
+func ParseScrollDownLines(s string) (lines int, err error) {
+	return parseCount(s, "ScrollDownLines", 'T', 1)
+}
+
+// ParseEraseInDisplayMode parses a sequence produced by EraseInDisplayMode
+func ParseEraseInDisplayMode(s string) (n int, err error) {
+	return parseCount(s, "EraseInDisplayMode", 'J', 0)
+}
+
+// ParseEraseInLineMode parses a sequence produced by EraseInLineMode
+func ParseEraseInLineMode(s string) (n int, err error) {
+	return parseCount(s, "EraseInLineMode", 'K', 0)
+}
+
+// parseMode parses an ANSI mode sequence with a single required parameter
+func parseMode(s, name string, final byte) (int, error) {
+	spec := csiSpec(name, final, 1)
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, err
+	}
+	mode := a.Param(0, OmittedParam)
+	if mode == OmittedParam {
+		return 0, spec.error(s)
+	}
+	return mode, nil
+}
+
+// ParseSetMode parses a sequence produced by SetMode
+func ParseSetMode(s string) (mode int, err error) {
+	return parseMode(s, "SetMode", 'h')
+}
+
+// ParseResetMode parses a sequence produced by ResetMode
+func ParseResetMode(s string) (mode int, err error) {
+	return parseMode(s, "ResetMode", 'l')
+}
+
+// ParseWindowManipulation parses a sequence produced by WindowManipulation
+func ParseWindowManipulation(s string) (ps int, args []int, err error) {
+	spec := csiSpec("WindowManipulation", 't', 3)
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(a.Params) == 0 {
+		return 0, nil, spec.error(s)
+	}
+	for i := 1; i < len(a.Params); i++ {
+		args = append(args, a.Param(i, 0))
+	}
+	return a.Param(0, 0), args, nil
+}
+
+// ParseSetScrollingRegion parses a sequence produced by SetScrollingRegion.
+// An omitted top margin defaults to 1 and an omitted bottom margin is
+// returned as 0, meaning the last line of the screen
+func ParseSetScrollingRegion(s string) (top, bottom int, err error) {
+	a, err := csiSpec("SetScrollingRegion", 'r', 2).parse(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Param(0, 1), a.Param(1, 0), nil
+}
+
+// ParseDeleteLines parses a sequence produced by DeleteLines
+func ParseDeleteLines(s string) (n int, err error) {
+	return parseCount(s, "DeleteLines", 'M', 1)
+}
+
+// ParseInsertLines parses a sequence produced by InsertLines
+func ParseInsertLines(s string) (n int, err error) {
+	return parseCount(s, "InsertLines", 'L', 1)
+}
+
+// ParseDeleteCharacters parses a sequence produced by DeleteCharacters
+func ParseDeleteCharacters(s string) (n int, err error) {
+	return parseCount(s, "DeleteCharacters", 'P', 1)
+}
+
+// ParseInsertCharacters parses a sequence produced by InsertCharacters
+func ParseInsertCharacters(s string) (n int, err error) {
+	return parseCount(s, "InsertCharacters", '@', 1)
+}
+
+// ParseSetGraphicsRendition parses a sequence produced by SetGraphicsRendition.
+// Omitted parameters are returned as 0
+func ParseSetGraphicsRendition(s string) (params []int, err error) {
+	a, err := csiSpec("SetGraphicsRendition", 'm', maxParams).parse(s)
+	if err != nil {
+		return nil, err
+	}
+	for i := range a.Params {
+		params = append(params, a.Param(i, 0))
+	}
+	return params, nil
+}
+
+// ParseRequestCursorPosition checks that s is the sequence returned by RequestCursorPosition
+func ParseRequestCursorPosition(s string) error {
+	spec := csiSpec("RequestCursorPosition", 'n', 1)
+	a, err := spec.parse(s)
+	if err != nil {
+		return err
+	}
+	if a.Param(0, 0) != 6 {
+		return spec.error(s)
+	}
+	return nil
+}
+
+// ParseReportCursorPosition parses a sequence produced by ReportCursorPosition.
+// Omitted coordinates default to 1
+func ParseReportCursorPosition(s string) (row, col int, err error) {
+	a, err := csiSpec("ReportCursorPosition", 'R', 2).parse(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Param(0, 1), a.Param(1, 1), nil
+}
+
+// charsetIntermediates maps the G0-G3 designators to their set numbers
+var charsetIntermediates = map[string]int{"(": 0, ")": 1, "*": 2, "+": 3}
+
+// parseCharset parses a character set designation accepting sets up to maxG
+func parseCharset(s, name string, maxG int) (int, byte, error) {
+	spec := sequenceSpec{name: name}
+	actions := ParseString(s)
+	if len(actions) != 1 || actions[0].Type != ActionESCDispatch {
+		return 0, 0, spec.error(s)
+	}
+	g, ok := charsetIntermediates[actions[0].Intermediates]
+	if !ok || g > maxG {
+		return 0, 0, spec.error(s)
+	}
+	return g, actions[0].Final, nil
+}
+
+// ParseSetCharacterSet parses a sequence produced by SetCharacterSet
+func ParseSetCharacterSet(s string) (g int, charset byte, err error) {
+	return parseCharset(s, "SetCharacterSet", 1)
+}
+
+// ParseDesignateCharacterSet parses a sequence produced by DesignateCharacterSet
+func ParseDesignateCharacterSet(s string) (g int, charset byte, err error) {
+	return parseCharset(s, "DesignateCharacterSet", 3)
+}
+
+// ParseSoftTerminalReset checks that s is the sequence returned by SoftTerminalReset
+func ParseSoftTerminalReset(s string) error {
+	_, err := sequenceSpec{name: "SoftTerminalReset", typ: ActionCSIDispatch, intermediates: "!", final: 'p'}.parse(s)
+	return err
+}
+
+// ParseRequestTerminalParameters checks that s is the sequence returned by RequestTerminalParameters
+func ParseRequestTerminalParameters(s string) error {
+	_, err := csiSpec("RequestTerminalParameters", 'x', 0).parse(s)
+	return err
+}
+
+// ParseSetConformanceLevel parses a sequence produced by SetConformanceLevel
+func ParseSetConformanceLevel(s string) (level int, err error) {
+	spec := sequenceSpec{name: "SetConformanceLevel", typ: ActionCSIDispatch, intermediates: "\"", final: 'p', maxParams: 2}
+	a, err := spec.parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return a.Param(0, 0), nil
+}
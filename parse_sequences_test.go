@@ -0,0 +1,192 @@
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestParseCountRoundTrip verifies that single-parameter builders and their parsers agree
+func TestParseCountRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(int) string
+		parse func(string) (int, error)
+	}{
+		{"CursorForward", CursorForward, ParseCursorForward},
+		{"CursorBackward", CursorBackward, ParseCursorBackward},
+		{"CursorDown", CursorDown, ParseCursorDown},
+		{"CursorUp", CursorUp, ParseCursorUp},
+		{"CursorNextLine", CursorNextLine, ParseCursorNextLine},
+		{"CursorPreviousLine", CursorPreviousLine, ParseCursorPreviousLine},
+		{"CursorHorizontalAbsolute", CursorHorizontalAbsolute, ParseCursorHorizontalAbsolute},
+		{"SetTextColor", SetTextColor, ParseSetTextColor},
+		{"SetBackgroundColor", SetBackgroundColor, ParseSetBackgroundColor},
+		{"ScrollUpLines", ScrollUpLines, ParseScrollUpLines},
+		{"ScrollDownLines", ScrollDownLines, ParseScrollDownLines},
+		{"EraseInDisplayMode", EraseInDisplayMode, ParseEraseInDisplayMode},
+		{"EraseInLineMode", EraseInLineMode, ParseEraseInLineMode},
+		{"SetMode", SetMode, ParseSetMode},
+		{"ResetMode", ResetMode, ParseResetMode},
+		{"DeleteLines", DeleteLines, ParseDeleteLines},
+		{"InsertLines", InsertLines, ParseInsertLines},
+		{"DeleteCharacters", DeleteCharacters, ParseDeleteCharacters},
+		{"InsertCharacters", InsertCharacters, ParseInsertCharacters},
+		{"SetConformanceLevel", SetConformanceLevel, ParseSetConformanceLevel},
+	}
+
+	for _, tt := range tests {
+		for _, n := range []int{0, 1, 3, 255} {
+			got, err := tt.parse(tt.build(n))
+			if err != nil || got != n {
+				t.Errorf("Parse%s(%s(%d)) = %d, %v, want %d, nil", tt.name, tt.name, n, got, err, n)
+			}
+		}
+	}
+}
+
+// TestParsePairRoundTrip verifies that two-parameter builders and their parsers agree
+func TestParsePairRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(int, int) string
+		parse func(string) (int, int, error)
+	}{
+		{"CursorPosition", CursorPosition, ParseCursorPosition},
+		{"SetScrollingRegion", SetScrollingRegion, ParseSetScrollingRegion},
+		{"ReportCursorPosition", ReportCursorPosition, ParseReportCursorPosition},
+	}
+
+	for _, tt := range tests {
+		for _, p := range [][2]int{{0, 0}, {1, 1}, {5, 10}, {24, 80}} {
+			a, b, err := tt.parse(tt.build(p[0], p[1]))
+			if err != nil || a != p[0] || b != p[1] {
+				t.Errorf("Parse%s(%s(%d, %d)) = %d, %d, %v", tt.name, tt.name, p[0], p[1], a, b, err)
+			}
+		}
+	}
+}
+
+// TestParseRGBRoundTrip verifies that RGB color builders and their parsers agree
+func TestParseRGBRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(int, int, int) string
+		parse func(string) (int, int, int, error)
+	}{
+		{"SetRGBTextColor", SetRGBTextColor, ParseSetRGBTextColor},
+		{"SetRGBBackgroundColor", SetRGBBackgroundColor, ParseSetRGBBackgroundColor},
+	}
+
+	for _, tt := range tests {
+		r, g, b, err := tt.parse(tt.build(255, 128, 0))
+		if err != nil || r != 255 || g != 128 || b != 0 {
+			t.Errorf("Parse%s = %d, %d, %d, %v, want 255, 128, 0, nil", tt.name, r, g, b, err)
+		}
+	}
+}
+
+// TestParseVariadicRoundTrip verifies WindowManipulation, SetGraphicsRendition and character set parsers
+func TestParseVariadicRoundTrip(t *testing.T) {
+	windowTests := []struct {
+		ps   int
+		args []int
+	}{
+		{1, nil},
+		{3, []int{100, 200}},
+		{8, []int{24, 80}},
+	}
+	for _, tt := range windowTests {
+		ps, args, err := ParseWindowManipulation(WindowManipulation(tt.ps, tt.args...))
+		if err != nil || ps != tt.ps || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("ParseWindowManipulation(WindowManipulation(%d, %v)) = %d, %v, %v", tt.ps, tt.args, ps, args, err)
+		}
+	}
+
+	for _, params := range [][]int{nil, {0}, {1, 4, 31}} {
+		got, err := ParseSetGraphicsRendition(SetGraphicsRendition(params...))
+		if err != nil || !reflect.DeepEqual(got, params) {
+			t.Errorf("ParseSetGraphicsRendition(SetGraphicsRendition(%v)) = %v, %v", params, got, err)
+		}
+	}
+
+	for g := 0; g <= 3; g++ {
+		gotG, charset, err := ParseDesignateCharacterSet(DesignateCharacterSet(g, '0'))
+		if err != nil || gotG != g || charset != '0' {
+			t.Errorf("ParseDesignateCharacterSet(DesignateCharacterSet(%d, '0')) = %d, %c, %v", g, gotG, charset, err)
+		}
+	}
+	for g := 0; g <= 1; g++ {
+		gotG, charset, err := ParseSetCharacterSet(SetCharacterSet(g, 'B'))
+		if err != nil || gotG != g || charset != 'B' {
+			t.Errorf("ParseSetCharacterSet(SetCharacterSet(%d, 'B')) = %d, %c, %v", g, gotG, charset, err)
+		}
+	}
+}
+
+// TestParseNoArgumentSequences verifies parsers for builders without parameters
+func TestParseNoArgumentSequences(t *testing.T) {
+	if err := ParseRequestCursorPosition(RequestCursorPosition()); err != nil {
+		t.Errorf("ParseRequestCursorPosition: %v", err)
+	}
+	if err := ParseSoftTerminalReset(SoftTerminalReset()); err != nil {
+		t.Errorf("ParseSoftTerminalReset: %v", err)
+	}
+	if err := ParseRequestTerminalParameters(RequestTerminalParameters()); err != nil {
+		t.Errorf("ParseRequestTerminalParameters: %v", err)
+	}
+}
+
+// TestParseDefaults verifies that omitted parameters use the sequence defaults
+func TestParseDefaults(t *testing.T) {
+	if row, col, err := ParseCursorPosition("\033[H"); err != nil || row != 1 || col != 1 {
+		t.Errorf("ParseCursorPosition(\"\\033[H\") = %d, %d, %v, want 1, 1, nil", row, col, err)
+	}
+	if n, err := ParseCursorUp("\033[A"); err != nil || n != 1 {
+		t.Errorf("ParseCursorUp(\"\\033[A\") = %d, %v, want 1, nil", n, err)
+	}
+	if n, err := ParseEraseInDisplayMode(EraseInLine); err == nil {
+		t.Errorf("ParseEraseInDisplayMode(EraseInLine) = %d, nil, want error", n)
+	}
+	if n, err := ParseScrollUpLines(ScrollUp); err != nil || n != 1 {
+		t.Errorf("ParseScrollUpLines(ScrollUp) = %d, %v, want 1, nil", n, err)
+	}
+}
+
+// TestParseErrors verifies that parsers reject other sequences
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) error
+		input string
+	}{
+		{"CursorUp with wrong final", func(s string) error { _, err := ParseCursorUp(s); return err }, CursorDown(1)},
+		{"CursorUp with trailing text", func(s string) error { _, err := ParseCursorUp(s); return err }, CursorUp(1) + "x"},
+		{"CursorPosition with private marker", func(s string) error { _, _, err := ParseCursorPosition(s); return err }, "\033[?1;1H"},
+		{"CursorPosition with extra parameter", func(s string) error { _, _, err := ParseCursorPosition(s); return err }, "\033[1;2;3H"},
+		{"SetTextColor as RGB", func(s string) error { _, err := ParseSetTextColor(s); return err }, SetRGBTextColor(1, 2, 3)},
+		{"SetMode without mode", func(s string) error { _, err := ParseSetMode(s); return err }, "\033[h"},
+		{"SetMode with DEC private mode", func(s string) error { _, err := ParseSetMode(s); return err }, EnterAltScreen},
+		{"SetCharacterSet with G2", func(s string) error { _, _, err := ParseSetCharacterSet(s); return err }, DesignateCharacterSet(2, 'B')},
+		{"SoftTerminalReset without intermediate", func(s string) error { return ParseSoftTerminalReset(s) }, "\033[p"},
+		{"Empty string", func(s string) error { return ParseRequestCursorPosition(s) }, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.parse(tt.input)
+		if !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("%s: error = %v, want ErrUnexpectedSequence", tt.name, err)
+		}
+	}
+}
+
+// ExampleParseCursorPosition demonstrates decoding a cursor position sequence
+func ExampleParseCursorPosition() {
+	row, column, err := ParseCursorPosition(CursorPosition(5, 10))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(row, column)
+}
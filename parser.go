@@ -0,0 +1,689 @@
+package terminal_go
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// ActionType identifies what a parsed Action asks the terminal to do
+type ActionType int
+
+const (
+	// ActionPrint displays a printable character
+	ActionPrint ActionType = iota
+	// ActionExecute executes a C0 or C1 control function
+	ActionExecute
+	// ActionESCDispatch dispatches an escape sequence
+	ActionESCDispatch
+	// ActionCSIDispatch dispatches a control sequence
+	ActionCSIDispatch
+	// ActionOSCDispatch dispatches an operating system command string
+	ActionOSCDispatch
+	// ActionDCSHook starts a device control string and selects its handler
+	ActionDCSHook
+	// ActionDCSPut passes device control string data to the active handler
+	ActionDCSPut
+	// ActionDCSUnhook ends a device control string
+	ActionDCSUnhook
+	// ActionStringDispatch dispatches an SOS, PM or APC string
+	ActionStringDispatch
+)
+
+var actionTypeNames = [...]string{
+	ActionPrint:          "Print",
+	ActionExecute:        "Execute",
+	ActionESCDispatch:    "ESCDispatch",
+	ActionCSIDispatch:    "CSIDispatch",
+	ActionOSCDispatch:    "OSCDispatch",
+	ActionDCSHook:        "DCSHook",
+	ActionDCSPut:         "DCSPut",
+	ActionDCSUnhook:      "DCSUnhook",
+	ActionStringDispatch: "StringDispatch",
+}
+
+// String returns the name of the action type
+func (t ActionType) String() string {
+	if t >= 0 && int(t) < len(actionTypeNames) {
+		return actionTypeNames[t]
+	}
+	return "ActionType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// OmittedParam marks a parameter or sub-parameter that was left empty in the sequence
+const OmittedParam = -1
+
+// Param is a single semicolon-separated parameter of a CSI or DCS sequence.
+// Param[0] is the parameter value and any further elements are its
+// colon-separated sub-parameters. Empty values are stored as OmittedParam.
+type Param []int
+
+// Value returns the parameter value, or def if it was omitted
+func (p Param) Value(def int) int {
+	if len(p) == 0 || p[0] == OmittedParam {
+		return def
+	}
+	return p[0]
+}
+
+// Sub returns the i-th sub-parameter (counting from 0), or def if it is missing or omitted
+func (p Param) Sub(i, def int) int {
+	if i < 0 || i+1 >= len(p) || p[i+1] == OmittedParam {
+		return def
+	}
+	return p[i+1]
+}
+
+// Action is a single unit of work produced by Parser
+type Action struct {
+	// Type selects which of the remaining fields are meaningful
+	Type ActionType
+	// Rune is the printed character or the executed control code
+	Rune rune
+	// Private is the private marker ('<', '=', '>' or '?') of a CSI or DCS sequence, or 0
+	Private byte
+	// Intermediates holds the intermediate bytes (0x20-0x2F) of an ESC, CSI or DCS sequence
+	Intermediates string
+	// Params holds the parameters of a CSI sequence or DCS hook. Only the
+	// first 32 are kept; as in the VT500, further ones are discarded
+	Params []Param
+	// Final is the final byte of an ESC, CSI or DCS sequence.
+	// For ActionStringDispatch it is the introducer: 'X' for SOS, '^' for PM, '_' for APC
+	Final byte
+	// Data holds the payload of OSC, SOS, PM and APC strings and DCS put actions
+	Data string
+	// Raw holds the bytes the action was parsed from
+	Raw string
+	// Truncated reports that the sequence exceeded the parser's MaxStringLength
+	// and the bytes past the limit were discarded from Data and Raw
+	Truncated bool
+	// Aborted reports that an ActionDCSUnhook ends a device control string
+	// cancelled by CAN or SUB rather than terminated by ST
+	Aborted bool
+}
+
+// Param returns the value of the i-th parameter, or def if it is missing or omitted
+func (a Action) Param(i, def int) int {
+	if i < 0 || i >= len(a.Params) {
+		return def
+	}
+	return a.Params[i].Value(def)
+}
+
+type parserState int
+
+// The ground, escape and CSI states execute C0 controls; they must stay
+// ordered before the DCS and string states.
+const (
+	stateGround parserState = iota
+	stateEscape
+	stateEscapeIntermediate
+	stateCSIEntry
+	stateCSIParam
+	stateCSIIntermediate
+	stateCSIIgnore
+	stateDCSEntry
+	stateDCSParam
+	stateDCSIntermediate
+	stateDCSPassthrough
+	stateDCSIgnore
+	stateOSCString
+	stateSOSPMAPCString
+	// stateStringEnd is entered when ESC arrives inside a string; the next
+	// byte decides whether it was ST (ESC \) or the start of a new sequence
+	stateStringEnd
+)
+
+const (
+	maxIntermediates = 2
+	maxParams        = 32
+	maxSubParams     = 16
+	maxParamValue    = 1<<31 - 1
+	// putChunkSize bounds how much DCS data is buffered before a put action is emitted
+	putChunkSize = 4096
+)
+
+// Parser is an implementation of the DEC VT500 escape sequence parser state
+// machine described at https://vt100.net/emu/dec_ansi_parser.
+// It turns a byte stream into Actions. Input is treated as UTF-8 unless
+// EightBit is set, and the parser keeps its state between calls to Parse,
+// so a sequence may be split across several calls.
+type Parser struct {
+	// EightBit makes the parser treat input as an 8-bit stream instead of
+	// UTF-8. Bytes 0x80-0x9F are then C1 controls, so 0x9B starts a control
+	// sequence, 0x9D an OSC string, 0x90 a DCS string and 0x9C is ST, while
+	// bytes 0xA0-0xFF are printed as Latin-1 characters.
+	EightBit bool
+
+	// MaxStringLength limits how many bytes of a single sequence are kept.
+	// Longer OSC, SOS, PM and APC strings are still consumed up to their
+	// terminator, but the excess is discarded and the action is marked
+	// Truncated. DCS data is streamed in put actions and is not limited.
+	// Zero means no limit.
+	MaxStringLength int
+
+	state       parserState
+	stringState parserState
+
+	private       byte
+	intermediates []byte
+	params        []Param
+	paramsFull    bool
+	ignoring      bool
+	truncated     bool
+	stringIntro   byte
+	data          []byte
+	raw           []byte
+
+	utf8Buf  [utf8.UTFMax]byte
+	utf8Len  int
+	utf8Need int
+
+	out []Action
+}
+
+// NewParser creates a parser in the ground state
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Reset returns the parser to the ground state and discards any partial sequence
+func (p *Parser) Reset() {
+	*p = Parser{EightBit: p.EightBit, MaxStringLength: p.MaxStringLength}
+}
+
+// Parse feeds data to the parser and returns the actions it produced
+func (p *Parser) Parse(data []byte) []Action {
+	p.out = nil
+	for _, b := range data {
+		p.advance(b)
+	}
+	p.flushPut()
+	out := p.out
+	p.out = nil
+	return out
+}
+
+// ParseString parses a complete string of terminal output and returns its actions
+func ParseString(s string) []Action {
+	return NewParser().Parse([]byte(s))
+}
+
+// flush ends the input: a partial UTF-8 character is printed as U+FFFD and
+// any unfinished sequence is discarded
+func (p *Parser) flush() []Action {
+	p.out = nil
+	if p.utf8Need > 0 {
+		p.invalidUTF8()
+	}
+	out := p.out
+	p.Reset()
+	return out
+}
+
+func (p *Parser) emit(a Action) {
+	p.out = append(p.out, a)
+}
+
+func (p *Parser) advance(b byte) {
+	if p.EightBit {
+		if b >= 0x80 && p.advanceEightBit(b) {
+			return
+		}
+	} else if p.state == stateGround && (p.utf8Need > 0 || b >= 0x80) {
+		p.advanceUTF8(b)
+		return
+	}
+
+	if p.state == stateStringEnd {
+		p.state = p.stringState
+		if b == '\\' {
+			p.raw = append(p.raw, b)
+			p.endString()
+			return
+		}
+		p.raw = p.raw[:len(p.raw)-1]
+		p.endString()
+		p.enterEscape()
+	}
+
+	switch b {
+	case 0x18, 0x1A:
+		p.abort()
+		p.execute(b)
+		return
+	case 0x1B:
+		if p.inString() {
+			p.flushPut()
+			p.stringState = p.state
+			p.state = stateStringEnd
+			p.raw = append(p.raw, b)
+			return
+		}
+		p.enterEscape()
+		return
+	}
+
+	if b < 0x20 && p.state <= stateCSIIgnore {
+		p.execute(b)
+		return
+	}
+
+	switch p.state {
+	case stateGround:
+		if b < 0x7F {
+			p.emit(Action{Type: ActionPrint, Rune: rune(b), Raw: string(b)})
+		}
+
+	case stateEscape:
+		p.record(b)
+		switch {
+		case b < 0x30:
+			p.collect(b)
+			p.state = stateEscapeIntermediate
+		case b == '[':
+			p.state = stateCSIEntry
+		case b == ']':
+			p.state = stateOSCString
+		case b == 'P':
+			p.state = stateDCSEntry
+		case b == 'X' || b == '^' || b == '_':
+			p.stringIntro = b
+			p.state = stateSOSPMAPCString
+		case b < 0x7F:
+			p.escDispatch(b)
+		}
+
+	case stateEscapeIntermediate:
+		p.record(b)
+		switch {
+		case b < 0x30:
+			p.collect(b)
+		case b < 0x7F:
+			p.escDispatch(b)
+		}
+
+	case stateCSIEntry, stateCSIParam, stateCSIIntermediate, stateCSIIgnore:
+		p.record(b)
+		p.advanceCSI(b)
+
+	case stateDCSEntry, stateDCSParam, stateDCSIntermediate:
+		p.record(b)
+		p.advanceDCS(b)
+
+	case stateDCSPassthrough:
+		if b != 0x7F {
+			p.data = append(p.data, b)
+			if len(p.data) >= putChunkSize {
+				p.flushPut()
+			}
+		}
+
+	case stateOSCString:
+		if b == 0x07 {
+			p.raw = append(p.raw, b)
+			p.endString()
+			return
+		}
+		if p.record(b) && b >= 0x20 {
+			p.data = append(p.data, b)
+		}
+
+	case stateSOSPMAPCString:
+		if p.record(b) && b >= 0x20 {
+			p.data = append(p.data, b)
+		}
+
+	case stateDCSIgnore:
+		p.record(b)
+	}
+}
+
+// advanceEightBit handles bytes 0x80-0xFF in EightBit mode. It returns
+// false for bytes that belong to the payload of a string
+func (p *Parser) advanceEightBit(b byte) bool {
+	if b >= 0xA0 {
+		switch {
+		case p.inString() || p.state == stateStringEnd:
+			return false
+		case p.state == stateGround:
+			p.emit(Action{Type: ActionPrint, Rune: rune(b), Raw: string([]byte{b})})
+		default:
+			// GR bytes inside escape and control sequences act as their GL equivalents
+			p.advance(b & 0x7F)
+		}
+		return true
+	}
+
+	if p.state == stateStringEnd {
+		p.state = p.stringState
+		p.raw = p.raw[:len(p.raw)-1]
+	}
+	if b == 0x9C && p.inString() {
+		p.raw = append(p.raw, b)
+		p.endString()
+		return true
+	}
+
+	// Any other C1 control ends the current string or sequence
+	if p.inString() {
+		p.endString()
+	}
+	p.clear()
+	p.state = stateGround
+	switch b {
+	case 0x9C:
+	case 0x90:
+		p.raw = append(p.raw, b)
+		p.state = stateDCSEntry
+	case 0x9B:
+		p.raw = append(p.raw, b)
+		p.state = stateCSIEntry
+	case 0x9D:
+		p.raw = append(p.raw, b)
+		p.state = stateOSCString
+	case 0x98, 0x9E, 0x9F:
+		p.raw = append(p.raw, b)
+		p.stringIntro = b - 0x40
+		p.state = stateSOSPMAPCString
+	default:
+		p.execute(b)
+	}
+	return true
+}
+
+func (p *Parser) advanceCSI(b byte) {
+	switch p.state {
+	case stateCSIEntry:
+		switch {
+		case b >= '<' && b <= '?':
+			p.private = b
+			p.state = stateCSIParam
+		case b >= '0' && b <= ';':
+			p.param(b)
+			p.state = stateCSIParam
+		case b < 0x30:
+			p.collect(b)
+			p.state = stateCSIIntermediate
+		case b < 0x7F:
+			p.csiDispatch(b)
+		}
+	case stateCSIParam:
+		switch {
+		case b >= '0' && b <= ';':
+			p.param(b)
+		case b >= '<' && b <= '?':
+			p.state = stateCSIIgnore
+		case b < 0x30:
+			p.collect(b)
+			p.state = stateCSIIntermediate
+		case b < 0x7F:
+			p.csiDispatch(b)
+		}
+	case stateCSIIntermediate:
+		switch {
+		case b < 0x30:
+			p.collect(b)
+		case b < 0x40:
+			p.state = stateCSIIgnore
+		case b < 0x7F:
+			p.csiDispatch(b)
+		}
+	case stateCSIIgnore:
+		if b >= 0x40 && b < 0x7F {
+			p.clear()
+			p.state = stateGround
+		}
+	}
+}
+
+func (p *Parser) advanceDCS(b byte) {
+	if b < 0x20 || b == 0x7F || b >= 0x80 {
+		return
+	}
+	switch p.state {
+	case stateDCSEntry:
+		switch {
+		case b >= '<' && b <= '?':
+			p.private = b
+			p.state = stateDCSParam
+		case b >= '0' && b <= ';':
+			p.param(b)
+			p.state = stateDCSParam
+		case b < 0x30:
+			p.collect(b)
+			p.state = stateDCSIntermediate
+		default:
+			p.hook(b)
+		}
+	case stateDCSParam:
+		switch {
+		case b >= '0' && b <= ';':
+			p.param(b)
+		case b >= '<' && b <= '?':
+			p.state = stateDCSIgnore
+		case b < 0x30:
+			p.collect(b)
+			p.state = stateDCSIntermediate
+		default:
+			p.hook(b)
+		}
+	case stateDCSIntermediate:
+		switch {
+		case b < 0x30:
+			p.collect(b)
+		case b < 0x40:
+			p.state = stateDCSIgnore
+		default:
+			p.hook(b)
+		}
+	}
+}
+
+func (p *Parser) advanceUTF8(b byte) {
+	if p.utf8Need > 0 {
+		if b&0xC0 == 0x80 {
+			p.utf8Buf[p.utf8Len] = b
+			p.utf8Len++
+			if p.utf8Len == p.utf8Need {
+				p.printUTF8()
+			}
+			return
+		}
+		p.invalidUTF8()
+		p.advance(b)
+		return
+	}
+
+	switch {
+	case b >= 0xC2 && b <= 0xDF:
+		p.utf8Need = 2
+	case b >= 0xE0 && b <= 0xEF:
+		p.utf8Need = 3
+	case b >= 0xF0 && b <= 0xF4:
+		p.utf8Need = 4
+	default:
+		p.emit(Action{Type: ActionPrint, Rune: utf8.RuneError, Raw: string([]byte{b})})
+		return
+	}
+	p.utf8Buf[0] = b
+	p.utf8Len = 1
+}
+
+func (p *Parser) printUTF8() {
+	buf := p.utf8Buf[:p.utf8Len]
+	p.utf8Len, p.utf8Need = 0, 0
+	r, size := utf8.DecodeRune(buf)
+	if r == utf8.RuneError && size <= 1 {
+		p.emit(Action{Type: ActionPrint, Rune: utf8.RuneError, Raw: string(buf)})
+		return
+	}
+	if r < 0xA0 {
+		p.emit(Action{Type: ActionExecute, Rune: r, Raw: string(buf)})
+		return
+	}
+	p.emit(Action{Type: ActionPrint, Rune: r, Raw: string(buf)})
+}
+
+func (p *Parser) invalidUTF8() {
+	raw := string(p.utf8Buf[:p.utf8Len])
+	p.utf8Len, p.utf8Need = 0, 0
+	p.emit(Action{Type: ActionPrint, Rune: utf8.RuneError, Raw: raw})
+}
+
+func (p *Parser) inString() bool {
+	switch p.state {
+	case stateDCSPassthrough, stateDCSIgnore, stateOSCString, stateSOSPMAPCString:
+		return true
+	}
+	return false
+}
+
+// record appends b to the raw bytes of the current sequence unless the
+// sequence has reached MaxStringLength
+func (p *Parser) record(b byte) bool {
+	if p.MaxStringLength > 0 && len(p.raw) >= p.MaxStringLength {
+		p.truncated = true
+		return false
+	}
+	p.raw = append(p.raw, b)
+	return true
+}
+
+func (p *Parser) clear() {
+	p.private = 0
+	p.intermediates = p.intermediates[:0]
+	p.params = nil
+	p.paramsFull = false
+	p.ignoring = false
+	p.truncated = false
+	p.stringIntro = 0
+	p.data = nil
+	p.raw = nil
+}
+
+func (p *Parser) enterEscape() {
+	p.clear()
+	p.raw = append(p.raw, 0x1B)
+	p.state = stateEscape
+}
+
+// abort cancels the current sequence, closing an open DCS handler first
+func (p *Parser) abort() {
+	if p.state == stateDCSPassthrough {
+		p.flushPut()
+		p.emit(Action{Type: ActionDCSUnhook, Aborted: true})
+	}
+	p.clear()
+	p.state = stateGround
+}
+
+func (p *Parser) execute(b byte) {
+	p.emit(Action{Type: ActionExecute, Rune: rune(b), Raw: string([]byte{b})})
+}
+
+func (p *Parser) collect(b byte) {
+	if len(p.intermediates) == maxIntermediates {
+		p.ignoring = true
+		return
+	}
+	p.intermediates = append(p.intermediates, b)
+}
+
+func (p *Parser) param(b byte) {
+	if p.params == nil {
+		p.params = []Param{{OmittedParam}}
+	}
+	if p.paramsFull {
+		// Parameters past maxParams are dropped, but the sequence is still dispatched
+		return
+	}
+	last := &p.params[len(p.params)-1]
+	switch b {
+	case ';':
+		if len(p.params) < maxParams {
+			p.params = append(p.params, Param{OmittedParam})
+		} else {
+			p.paramsFull = true
+		}
+	case ':':
+		if len(*last) <= maxSubParams {
+			*last = append(*last, OmittedParam)
+		}
+	default:
+		i := len(*last) - 1
+		v := (*last)[i]
+		if v == OmittedParam {
+			v = 0
+		}
+		v = v*10 + int(b-'0')
+		if v > maxParamValue {
+			v = maxParamValue
+		}
+		(*last)[i] = v
+	}
+}
+
+func (p *Parser) action(t ActionType, final byte) Action {
+	return Action{
+		Type:          t,
+		Private:       p.private,
+		Intermediates: string(p.intermediates),
+		Params:        p.params,
+		Final:         final,
+		Raw:           string(p.raw),
+		Truncated:     p.truncated,
+	}
+}
+
+func (p *Parser) escDispatch(b byte) {
+	if !p.ignoring {
+		p.emit(p.action(ActionESCDispatch, b))
+	}
+	p.clear()
+	p.state = stateGround
+}
+
+func (p *Parser) csiDispatch(b byte) {
+	if !p.ignoring {
+		p.emit(p.action(ActionCSIDispatch, b))
+	}
+	p.clear()
+	p.state = stateGround
+}
+
+func (p *Parser) hook(b byte) {
+	if p.ignoring {
+		p.state = stateDCSIgnore
+		return
+	}
+	p.emit(p.action(ActionDCSHook, b))
+	p.clear()
+	p.state = stateDCSPassthrough
+}
+
+func (p *Parser) flushPut() {
+	if p.state != stateDCSPassthrough || len(p.data) == 0 {
+		return
+	}
+	p.emit(Action{Type: ActionDCSPut, Data: string(p.data), Raw: string(p.data)})
+	p.data = p.data[:0]
+}
+
+// endString finishes the current OSC, DCS or SOS/PM/APC string and returns to ground
+func (p *Parser) endString() {
+	switch p.state {
+	case stateOSCString:
+		a := p.action(ActionOSCDispatch, 0)
+		a.Data = string(p.data)
+		p.emit(a)
+	case stateSOSPMAPCString:
+		a := p.action(ActionStringDispatch, p.stringIntro)
+		a.Data = string(p.data)
+		p.emit(a)
+	case stateDCSPassthrough:
+		p.flushPut()
+		p.emit(Action{Type: ActionDCSUnhook, Raw: string(p.raw)})
+	}
+	p.clear()
+	p.state = stateGround
+}
@@ -0,0 +1,201 @@
+package terminal_go
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestParserActions verifies that the parser produces the expected actions for sequences
+// emitted by the builders and constants of this package
+func TestParserActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Action
+	}{
+		{"Print and execute", "a\nb", []Action{
+			{Type: ActionPrint, Rune: 'a', Raw: "a"},
+			{Type: ActionExecute, Rune: '\n', Raw: "\n"},
+			{Type: ActionPrint, Rune: 'b', Raw: "b"},
+		}},
+		{"UTF-8", "é€", []Action{
+			{Type: ActionPrint, Rune: 'é', Raw: "é"},
+			{Type: ActionPrint, Rune: '€', Raw: "€"},
+		}},
+		{"Invalid UTF-8", "\xe2\x82x\xff", []Action{
+			{Type: ActionPrint, Rune: '�', Raw: "\xe2\x82"},
+			{Type: ActionPrint, Rune: 'x', Raw: "x"},
+			{Type: ActionPrint, Rune: '�', Raw: "\xff"},
+		}},
+		{"CursorPosition", CursorPosition(5, 10), []Action{
+			{Type: ActionCSIDispatch, Params: []Param{{5}, {10}}, Final: 'H', Raw: "\033[5;10H"},
+		}},
+		{"EnterAltScreen", EnterAltScreen, []Action{
+			{Type: ActionCSIDispatch, Private: '?', Params: []Param{{1049}}, Final: 'h', Raw: EnterAltScreen},
+		}},
+		{"EraseInLine", EraseInLine, []Action{
+			{Type: ActionCSIDispatch, Final: 'K', Raw: EraseInLine},
+		}},
+		{"Omitted parameter", "\033[;5H", []Action{
+			{Type: ActionCSIDispatch, Params: []Param{{OmittedParam}, {5}}, Final: 'H', Raw: "\033[;5H"},
+		}},
+		{"Sub-parameters", "\033[38:2::10:20:30m", []Action{
+			{Type: ActionCSIDispatch, Params: []Param{{38, 2, OmittedParam, 10, 20, 30}}, Final: 'm', Raw: "\033[38:2::10:20:30m"},
+		}},
+		{"SetConformanceLevel", SetConformanceLevel(2), []Action{
+			{Type: ActionCSIDispatch, Intermediates: "\"", Params: []Param{{2}}, Final: 'p', Raw: "\033[2\"p"},
+		}},
+		{"SoftTerminalReset", SoftTerminalReset(), []Action{
+			{Type: ActionCSIDispatch, Intermediates: "!", Final: 'p', Raw: "\033[!p"},
+		}},
+		{"Execute inside CSI", "\033[1\n;2H", []Action{
+			{Type: ActionExecute, Rune: '\n', Raw: "\n"},
+			{Type: ActionCSIDispatch, Params: []Param{{1}, {2}}, Final: 'H', Raw: "\033[1;2H"},
+		}},
+		{"Private marker after parameter is ignored", "\033[1?hx", []Action{
+			{Type: ActionPrint, Rune: 'x', Raw: "x"},
+		}},
+		{"ReverseIndex", ReverseIndex, []Action{
+			{Type: ActionESCDispatch, Final: 'M', Raw: ReverseIndex},
+		}},
+		{"DesignateCharacterSet", DesignateCharacterSet(2, '0'), []Action{
+			{Type: ActionESCDispatch, Intermediates: "*", Final: '0', Raw: "\033*0"},
+		}},
+		{"OSC terminated by BEL", "\033]0;title\a", []Action{
+			{Type: ActionOSCDispatch, Data: "0;title", Raw: "\033]0;title\a"},
+		}},
+		{"OSC terminated by ST", "\033]8;;https://example.com\033\\link", []Action{
+			{Type: ActionOSCDispatch, Data: "8;;https://example.com", Raw: "\033]8;;https://example.com\033\\"},
+			{Type: ActionPrint, Rune: 'l', Raw: "l"},
+			{Type: ActionPrint, Rune: 'i', Raw: "i"},
+			{Type: ActionPrint, Rune: 'n', Raw: "n"},
+			{Type: ActionPrint, Rune: 'k', Raw: "k"},
+		}},
+		{"OSC terminated by another sequence", "\033]2;x\033[m", []Action{
+			{Type: ActionOSCDispatch, Data: "2;x", Raw: "\033]2;x"},
+			{Type: ActionCSIDispatch, Final: 'm', Raw: "\033[m"},
+		}},
+		{"DCS", "\033P1$r0;1m\033\\", []Action{
+			{Type: ActionDCSHook, Intermediates: "$", Params: []Param{{1}}, Final: 'r', Raw: "\033P1$r"},
+			{Type: ActionDCSPut, Data: "0;1m", Raw: "0;1m"},
+			{Type: ActionDCSUnhook, Raw: "\033\\"},
+		}},
+		{"APC", "\033_Gf=100\033\\", []Action{
+			{Type: ActionStringDispatch, Final: '_', Data: "Gf=100", Raw: "\033_Gf=100\033\\"},
+		}},
+		{"CAN aborts sequence", "\033[12\x18x", []Action{
+			{Type: ActionExecute, Rune: 0x18, Raw: "\x18"},
+			{Type: ActionPrint, Rune: 'x', Raw: "x"},
+		}},
+		{"CAN aborts DCS", "\033Pqab\x18", []Action{
+			{Type: ActionDCSHook, Final: 'q', Raw: "\033Pq"},
+			{Type: ActionDCSPut, Data: "ab", Raw: "ab"},
+			{Type: ActionDCSUnhook, Aborted: true},
+			{Type: ActionExecute, Rune: 0x18, Raw: "\x18"},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := ParseString(tt.input)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseString(%q) = %+v, want %+v", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestParserSplitInput verifies that sequences and UTF-8 characters split across
+// several calls to Parse produce the same actions as unsplit input
+func TestParserSplitInput(t *testing.T) {
+	inputs := []string{
+		SetRGBTextColor(10, 20, 30) + "héllo" + ResetAllAttributes,
+		"\033]8;;https://example.com\033\\link\033]8;;\033\\",
+		"\033P1$r0m\033\\€",
+	}
+
+	for _, input := range inputs {
+		want := ParseString(input)
+		for split := 1; split < len(input); split++ {
+			p := NewParser()
+			got := append(p.Parse([]byte(input[:split])), p.Parse([]byte(input[split:]))...)
+			if !reflect.DeepEqual(got, want) && !equalIgnoringPuts(got, want) {
+				t.Errorf("Parse(%q) split at %d = %+v, want %+v", input, split, got, want)
+			}
+		}
+	}
+}
+
+// equalIgnoringPuts compares actions after merging consecutive DCS put actions
+func equalIgnoringPuts(a, b []Action) bool {
+	return reflect.DeepEqual(mergePuts(a), mergePuts(b))
+}
+
+func mergePuts(actions []Action) []Action {
+	var out []Action
+	for _, a := range actions {
+		if n := len(out); n > 0 && a.Type == ActionDCSPut && out[n-1].Type == ActionDCSPut {
+			out[n-1].Data += a.Data
+			out[n-1].Raw += a.Raw
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// TestActionParam verifies that parameter accessors fall back to defaults
+func TestActionParam(t *testing.T) {
+	a := ParseString("\033[;5;38:2::7H")[0]
+	if got := a.Param(0, 1); got != 1 {
+		t.Errorf("Param(0, 1) = %d, want 1", got)
+	}
+	if got := a.Param(1, 1); got != 5 {
+		t.Errorf("Param(1, 1) = %d, want 5", got)
+	}
+	if got := a.Param(3, 9); got != 9 {
+		t.Errorf("Param(3, 9) = %d, want 9", got)
+	}
+	if got := a.Params[2].Sub(0, 0); got != 2 {
+		t.Errorf("Sub(0, 0) = %d, want 2", got)
+	}
+	if got := a.Params[2].Sub(1, 4); got != 4 {
+		t.Errorf("Sub(1, 4) = %d, want 4", got)
+	}
+	if got := a.Params[2].Sub(2, 0); got != 7 {
+		t.Errorf("Sub(2, 0) = %d, want 7", got)
+	}
+}
+
+// TestParserParamLimit verifies that parameters past the limit are dropped
+// without losing the sequence
+func TestParserParamLimit(t *testing.T) {
+	params := make([]string, 40)
+	for i := range params {
+		params[i] = strconv.Itoa(i + 1)
+	}
+	seq := "\033[" + strings.Join(params, ";") + ":5m"
+	actions := ParseString(seq + "x")
+	if len(actions) != 2 || actions[0].Type != ActionCSIDispatch || actions[0].Final != 'm' || actions[1].Rune != 'x' {
+		t.Fatalf("ParseString(40 parameters) = %+v, want an SGR dispatch and a print", actions)
+	}
+	a := actions[0]
+	if len(a.Params) != maxParams || a.Param(maxParams-1, 0) != maxParams || a.Raw != seq {
+		t.Errorf("SGR with 40 parameters kept %d, the last %d, Raw %q; want %d, the last %d, Raw %q",
+			len(a.Params), a.Param(len(a.Params)-1, 0), a.Raw, maxParams, maxParams, seq)
+	}
+
+	// The last parameter kept is not extended by the digits that follow it
+	a = ParseString("\033[" + strings.Repeat("1;", maxParams) + "99H")[0]
+	if len(a.Params) != maxParams || a.Param(maxParams-1, 0) != 1 {
+		t.Errorf("ParseString(%d parameters) = %+v, want %d parameters of 1", maxParams+1, a.Params, maxParams)
+	}
+}
+
+// ExampleParseString demonstrates decoding emitted sequences back into actions
+func ExampleParseString() {
+	for _, a := range ParseString(CursorPosition(5, 10) + "Hi") {
+		fmt.Println(a.Type, a.Raw)
+	}
+}
@@ -0,0 +1,50 @@
+package terminal_go
+
+import "bytes"
+
+// pasteStart and pasteEnd wrap pasted text when EnableBracketedPaste is in effect
+const (
+	pasteStart = "\033[200~"
+	pasteEnd   = "\033[201~"
+)
+
+// PasteEvent is text pasted into the terminal with bracketed paste enabled
+type PasteEvent struct {
+	// Text is the pasted text, sanitized with InputDecoder.PastePolicy if set
+	Text string
+}
+
+func (PasteEvent) isEvent() {}
+
+// PasteSanitizePolicy returns the policy recommended for
+// InputDecoder.PastePolicy: it keeps tabs and line breaks and removes every
+// other control character and escape sequence from pasted text
+func PasteSanitizePolicy() SanitizePolicy {
+	return SanitizePolicy{Mode: SanitizeStrip, Controls: "\t\n\r"}
+}
+
+// pasteText collects pasted text up to the end marker. It consumes as much
+// of buf as it can, keeping back a possible start of the end marker, and
+// returns the paste once the marker is found
+func (d *InputDecoder) pasteText(buf []byte) (Event, int, bool) {
+	if i := bytes.Index(buf, []byte(pasteEnd)); i >= 0 {
+		d.paste = append(d.paste, buf[:i]...)
+		text := string(d.paste)
+		if d.PastePolicy != nil {
+			text = d.PastePolicy.Sanitize(text)
+		}
+		d.pasting, d.paste = false, nil
+		return PasteEvent{Text: text}, i + len(pasteEnd), true
+	}
+
+	n := len(buf)
+	if i := bytes.LastIndexByte(buf, 0x1B); i >= 0 && len(buf)-i < len(pasteEnd) &&
+		bytes.HasPrefix([]byte(pasteEnd), buf[i:]) {
+		n = i
+	}
+	if n == 0 {
+		return nil, 0, false
+	}
+	d.paste = append(d.paste, buf[:n]...)
+	return nil, n, true
+}
@@ -0,0 +1,72 @@
+package terminal_go
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestDecodeInputPaste verifies that bracketed pastes are collected into a single event
+func TestDecodeInputPaste(t *testing.T) {
+	input := "a" + pasteStart + "hello\r\033[Aworld" + pasteEnd + "b"
+	want := []Event{
+		KeyEvent{Key: KeyRune, Rune: 'a'},
+		PasteEvent{Text: "hello\r\033[Aworld"},
+		KeyEvent{Key: KeyRune, Rune: 'b'},
+	}
+
+	for size := 1; size <= len(input); size++ {
+		d := NewInputDecoder()
+		var got []Event
+		for i := 0; i < len(input); i += size {
+			got = append(got, d.Feed([]byte(input[i:min(i+size, len(input))]))...)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("chunk size %d: got %+v, want %+v", size, got, want)
+		}
+		if d.Pending() {
+			t.Errorf("chunk size %d: decoder still pending after the paste", size)
+		}
+	}
+}
+
+// TestInputDecoderPasteFlush verifies that Flush does not cut a paste short
+func TestInputDecoderPasteFlush(t *testing.T) {
+	d := NewInputDecoder()
+	d.Feed([]byte(pasteStart + "abc\033[20"))
+	if got := d.Flush(); len(got) != 0 {
+		t.Errorf("Flush during a paste = %+v, want no events", got)
+	}
+	if !d.Pending() {
+		t.Error("Pending() = false during a paste")
+	}
+	got := d.Feed([]byte("0~x\033[201~"))
+	want := []Event{PasteEvent{Text: "abc\033[200~x"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Feed = %+v, want %+v", got, want)
+	}
+}
+
+// TestInputDecoderPastePolicy verifies that pasted text is sanitized with PastePolicy
+func TestInputDecoderPastePolicy(t *testing.T) {
+	policy := PasteSanitizePolicy()
+	d := NewInputDecoder()
+	d.PastePolicy = &policy
+	got := d.Feed([]byte(pasteStart + "rm -rf\t~\r\033]0;x\a\033[31mred\x03" + pasteEnd))
+	want := []Event{PasteEvent{Text: "rm -rf\t~\rred"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Feed = %+v, want %+v", got, want)
+	}
+}
+
+func ExamplePasteEvent() {
+	fmt.Printf("%q\n", EnableBracketedPaste)
+	for _, ev := range DecodeInput("\033[200~two\rlines\033[201~") {
+		if p, ok := ev.(PasteEvent); ok {
+			fmt.Printf("pasted %q\n", p.Text)
+		}
+	}
+	// Output:
+	// "\x1b[?2004h"
+	// pasted "two\rlines"
+}
@@ -0,0 +1,162 @@
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoReply is returned by Query when the terminal answers the sentinel
+// but not the request, which means it does not support the request
+var ErrNoReply = errors.New("terminal_go: no reply to query")
+
+// Query writes request to the terminal followed by a Primary Device
+// Attributes request, and returns the control sequences the terminal sends
+// back before the Primary Device Attributes reply. Terminals answer in
+// order, so a request they do not support yields ErrNoReply as soon as the
+// sentinel's reply arrives instead of a wait for a reply that never comes.
+//
+// The reply is returned as raw sequences for the Parse functions, e.g.
+// ParseReportCursorPosition for RequestCursorPosition. Typed characters
+// that arrive during the query are discarded, as is anything after the
+// sentinel's reply; a program that is already reading input should use
+// InputRouter instead.
+//
+// Query returns ctx.Err() when ctx is done. The wait can be interrupted if
+// tty supports read deadlines, like a net.Conn, or is an *os.File terminal;
+// for other readers ctx is only checked between reads. The terminal should
+// be in raw mode so that replies are not echoed or held in a line buffer
+func Query(ctx context.Context, tty io.ReadWriter, request string) (string, error) {
+	// A request for device attributes gets its own reply before the sentinel's
+	expect := countDA1Requests(request) + 1
+	if _, err := io.WriteString(tty, request+RequestPrimaryDeviceAttributes); err != nil {
+		return "", err
+	}
+
+	r, err := newContextReader(ctx, tty)
+	if err != nil {
+		return "", err
+	}
+	defer r.close()
+
+	d := NewDecoder()
+	var reply strings.Builder
+	buf := make([]byte, 256)
+	for {
+		n, err := r.read(buf)
+		for _, tok := range d.Feed(buf[:n]) {
+			if isDA1Reply(tok) {
+				if expect--; expect == 0 {
+					if reply.Len() == 0 {
+						return "", ErrNoReply
+					}
+					return reply.String(), nil
+				}
+			}
+			if tok.Type != TokenText && tok.Type != TokenControl {
+				reply.WriteString(tok.Raw)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// countDA1Requests counts the Primary Device Attributes requests in s
+func countDA1Requests(s string) int {
+	n := 0
+	for _, a := range ParseString(s) {
+		if a.Type == ActionCSIDispatch && a.Private == 0 && a.Intermediates == "" && a.Final == 'c' && a.Param(0, 0) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// isDA1Reply reports whether tok is a Primary Device Attributes reply, CSI ? Ps c
+func isDA1Reply(tok Token) bool {
+	return tok.Type == TokenCSI && tok.Action.Private == '?' && tok.Action.Intermediates == "" && tok.Action.Final == 'c'
+}
+
+// contextReader reads from a terminal until a context is done
+type contextReader struct {
+	ctx    context.Context
+	r      io.Reader
+	close  func()
+	poller *ttyPoller
+}
+
+// newContextReader prepares r for reads that end when ctx is done. It
+// uses read deadlines where r supports them and a ttyPoller for *os.File
+// terminals without deadline support
+func newContextReader(ctx context.Context, r io.Reader) (*contextReader, error) {
+	cr := &contextReader{ctx: ctx, r: r, close: func() {}}
+
+	type deadliner interface{ SetReadDeadline(time.Time) error }
+	if dl, ok := r.(deadliner); ok {
+		// The deadline is only set once ctx is done, so a read that times
+		// out always has ctx.Err() to report
+		if err := dl.SetReadDeadline(time.Time{}); err == nil {
+			var mu sync.Mutex
+			done := false
+			stop := context.AfterFunc(ctx, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if !done {
+					dl.SetReadDeadline(time.Unix(1, 0))
+				}
+			})
+			cr.close = func() {
+				stop()
+				mu.Lock()
+				defer mu.Unlock()
+				done = true
+				dl.SetReadDeadline(time.Time{})
+			}
+			return cr, nil
+		}
+	}
+
+	if f, ok := r.(*os.File); ok {
+		poller, err := newTTYPoller(int(f.Fd()))
+		if err == nil {
+			cr.poller = poller
+			stop := context.AfterFunc(ctx, poller.wake)
+			cr.close = func() {
+				stop()
+				poller.close()
+			}
+			return cr, nil
+		}
+	}
+	return cr, nil
+}
+
+// read reads from the terminal, returning ctx.Err() once the context is done
+func (cr *contextReader) read(p []byte) (int, error) {
+	for cr.poller != nil {
+		if err := cr.ctx.Err(); err != nil {
+			return 0, err
+		}
+		ready, err := cr.poller.wait(-1)
+		if err != nil {
+			return 0, err
+		}
+		if ready {
+			break
+		}
+	}
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := cr.r.Read(p)
+	if err != nil && cr.ctx.Err() != nil {
+		return n, cr.ctx.Err()
+	}
+	return n, err
+}
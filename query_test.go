@@ -0,0 +1,108 @@
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeTerminal answers queries on one end of a pipe and returns the other
+// end. Requests found in replies get that reply and Primary Device
+// Attributes requests get a VT220 reply; anything else is ignored
+func fakeTerminal(t *testing.T, replies map[string]string) net.Conn {
+	t.Helper()
+	tty, term := net.Pipe()
+	t.Cleanup(func() {
+		tty.Close()
+		term.Close()
+	})
+	go func() {
+		d := NewDecoder()
+		buf := make([]byte, 1024)
+		for {
+			n, err := term.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, tok := range d.Feed(buf[:n]) {
+				reply, ok := replies[tok.Raw]
+				if tok.Raw == RequestPrimaryDeviceAttributes {
+					reply, ok = "\033[?62;22c", true
+				}
+				if ok {
+					term.Write([]byte(reply))
+				}
+			}
+		}
+	}()
+	return tty
+}
+
+// TestQuery verifies that Query returns the reply sent before the sentinel's
+func TestQuery(t *testing.T) {
+	tty := fakeTerminal(t, map[string]string{
+		RequestCursorPosition(): "\033[5;10R",
+		QueryKittyKeyboard:      "x\033[?1u",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reply, err := Query(ctx, tty, RequestCursorPosition())
+	if err != nil {
+		t.Fatalf("Query(CPR) error: %v", err)
+	}
+	if row, col, err := ParseReportCursorPosition(reply); err != nil || row != 5 || col != 10 {
+		t.Errorf("ParseReportCursorPosition(%q) = %d, %d, %v, want 5, 10, nil", reply, row, col, err)
+	}
+
+	// Typed characters are not part of the reply
+	if reply, err := Query(ctx, tty, QueryKittyKeyboard); err != nil || reply != "\033[?1u" {
+		t.Errorf("Query(QueryKittyKeyboard) = %q, %v, want %q, nil", reply, err, "\033[?1u")
+	}
+
+	// The sentinel itself can be queried
+	if reply, err := Query(ctx, tty, RequestPrimaryDeviceAttributes); err != nil || reply != "\033[?62;22c" {
+		t.Errorf("Query(DA1) = %q, %v, want %q, nil", reply, err, "\033[?62;22c")
+	}
+
+	if reply, err := Query(ctx, tty, QueryModifyOtherKeys); !errors.Is(err, ErrNoReply) {
+		t.Errorf("Query(unsupported) = %q, %v, want ErrNoReply", reply, err)
+	}
+}
+
+// TestQueryContext verifies that Query gives up when its context is done
+func TestQueryContext(t *testing.T) {
+	tty, term := net.Pipe()
+	defer tty.Close()
+	defer term.Close()
+	// A terminal that reads requests but never answers
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			if _, err := term.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Query(ctx, tty, RequestCursorPosition()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Query() with a deadline = %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := Query(ctx, tty, RequestCursorPosition()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Query() after cancel = %v, want context.Canceled", err)
+	}
+
+	// The deadline is cleared afterwards
+	go term.Write([]byte("a"))
+	tty.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if n, err := tty.Read(make([]byte, 1)); n != 1 || err != nil {
+		t.Errorf("Read() after Query = %d, %v, want 1, nil", n, err)
+	}
+}
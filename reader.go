@@ -0,0 +1,216 @@
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrReaderClosed is returned by InputReader.ReadEvent after Close
+var ErrReaderClosed = errors.New("terminal_go: input reader closed")
+
+// ResizeEvent reports a new terminal size after the window was resized
+type ResizeEvent struct {
+	// Width and Height are the size in columns and rows
+	Width, Height int
+}
+
+func (ResizeEvent) isEvent() {}
+
+// InputReader reads events from a terminal. It waits for input with
+// poll(2) or select(2) rather than blocking in read(2), so ReadEvent returns
+// as soon as its context is done or the reader is closed, and no goroutine
+// is left behind reading the terminal.
+//
+// The reader does not change the terminal's modes: put it in raw mode
+// before reading, and Close the reader before restoring the terminal and
+// handing it to a child process such as $EDITOR. A new reader can be
+// created on the same terminal afterwards.
+type InputReader struct {
+	// Decoder turns the bytes read into events. Set its EscapeTimeout or
+	// PastePolicy before the first call to ReadEvent
+	Decoder *InputDecoder
+
+	tty    *os.File
+	fd     int
+	poller *ttyPoller
+	buf    []byte
+	queue  []Event
+
+	signals chan os.Signal
+	resized atomic.Bool
+	stopped chan struct{}
+
+	// readMu is held by ReadEvent, so Close can wait for it to return
+	readMu    sync.Mutex
+	closeOnce sync.Once
+	closed    atomic.Bool
+
+	errMu sync.Mutex
+	err   error
+}
+
+// NewInputReader creates a reader for the terminal tty, usually os.Stdin.
+// It returns an error wrapping errors.ErrUnsupported on platforms without
+// a poller for terminals
+func NewInputReader(tty *os.File) (*InputReader, error) {
+	fd := int(tty.Fd())
+	poller, err := newTTYPoller(fd)
+	if err != nil {
+		return nil, err
+	}
+	r := &InputReader{
+		Decoder: NewInputDecoder(),
+		tty:     tty,
+		fd:      fd,
+		poller:  poller,
+		buf:     make([]byte, 4096),
+		signals: make(chan os.Signal, 1),
+		stopped: make(chan struct{}),
+	}
+	notifyResize(r.signals)
+	go r.forwardSignals()
+	return r, nil
+}
+
+// forwardSignals wakes a waiting ReadEvent when the window is resized.
+// It runs until Close
+func (r *InputReader) forwardSignals() {
+	defer close(r.stopped)
+	for range r.signals {
+		r.resized.Store(true)
+		r.poller.wake()
+	}
+}
+
+// ReadEvent returns the next key, mouse, paste, focus, resize or unknown
+// event. It returns ctx.Err() when ctx is done, io.EOF when the terminal is
+// closed and ErrReaderClosed after Close. ReadEvent must not be called from
+// several goroutines at once
+func (r *InputReader) ReadEvent(ctx context.Context) (Event, error) {
+	r.readMu.Lock()
+	defer r.readMu.Unlock()
+
+	stop := context.AfterFunc(ctx, r.poller.wake)
+	defer stop()
+
+	for {
+		if r.closed.Load() {
+			return nil, ErrReaderClosed
+		}
+		if len(r.queue) > 0 {
+			ev := r.queue[0]
+			r.queue = r.queue[1:]
+			return ev, nil
+		}
+		if r.resized.Swap(false) {
+			if width, height, err := windowSize(r.fd); err == nil {
+				return ResizeEvent{Width: width, Height: height}, nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		timeout := time.Duration(-1)
+		if deadline, ok := r.Decoder.Deadline(); ok {
+			timeout = max(time.Until(deadline), 0)
+		}
+		ready, err := r.poller.wait(timeout)
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			// Timed out or woken up: give up on a lone ESC if it is time
+			r.queue = append(r.queue, r.Decoder.Expire(time.Now())...)
+			continue
+		}
+
+		n, err := r.tty.Read(r.buf)
+		r.queue = append(r.queue, r.Decoder.Feed(r.buf[:n])...)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				r.queue = append(r.queue, r.Decoder.Flush()...)
+				if len(r.queue) > 0 {
+					continue
+				}
+			}
+			return nil, err
+		}
+		if n == 0 && len(r.queue) == 0 {
+			return nil, io.EOF
+		}
+	}
+}
+
+// takeQueued removes and returns the events decoded but not yet returned
+func (r *InputReader) takeQueued() []Event {
+	r.readMu.Lock()
+	defer r.readMu.Unlock()
+	queue := r.queue
+	r.queue = nil
+	return queue
+}
+
+// Events starts a goroutine that reads events with ReadEvent and sends them
+// on the returned channel. The channel is closed when ctx is done, the
+// reader is closed or reading fails; Err reports why. Do not call ReadEvent
+// while the channel is open
+func (r *InputReader) Events(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for {
+			ev, err := r.ReadEvent(ctx)
+			if err != nil {
+				r.setErr(err)
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				r.setErr(ctx.Err())
+				return
+			case <-r.stopped:
+				r.setErr(ErrReaderClosed)
+				return
+			}
+		}
+	}()
+	return events
+}
+
+func (r *InputReader) setErr(err error) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	r.err = err
+}
+
+// Err returns the error that closed the channel returned by Events
+func (r *InputReader) Err() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return r.err
+}
+
+// Close stops the reader, waiting for a ReadEvent in progress to return
+// ErrReaderClosed. Input read from the terminal but not yet returned as
+// events is discarded. Close does not close the terminal itself
+func (r *InputReader) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		r.closed.Store(true)
+		stopResize(r.signals)
+		close(r.signals)
+		<-r.stopped
+		r.poller.wake()
+		r.readMu.Lock()
+		defer r.readMu.Unlock()
+		err = r.poller.close()
+	})
+	return err
+}
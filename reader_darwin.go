@@ -0,0 +1,60 @@
+package terminal_go
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ttyPoller waits for a terminal to become readable with select(2), since
+// kqueue does not support terminal devices on macOS
+type ttyPoller struct {
+	*wakePipe
+	fd int
+}
+
+func newTTYPoller(fd int) (*ttyPoller, error) {
+	pipe, err := newWakePipe()
+	if err != nil {
+		return nil, err
+	}
+	if fd >= syscall.FD_SETSIZE || pipe.r >= syscall.FD_SETSIZE {
+		pipe.close()
+		return nil, fmt.Errorf("terminal_go: file descriptor %d is too large for select", max(fd, pipe.r))
+	}
+	return &ttyPoller{wakePipe: pipe, fd: fd}, nil
+}
+
+func fdSet(set *syscall.FdSet, fd int) {
+	set.Bits[fd/32] |= 1 << (uint(fd) % 32)
+}
+
+func fdIsSet(set *syscall.FdSet, fd int) bool {
+	return set.Bits[fd/32]&(1<<(uint(fd)%32)) != 0
+}
+
+// wait blocks until the terminal is readable, wake is called or timeout
+// passes, and reports whether the terminal is readable. A negative timeout
+// waits indefinitely
+func (p *ttyPoller) wait(timeout time.Duration) (bool, error) {
+	var set syscall.FdSet
+	fdSet(&set, p.fd)
+	fdSet(&set, p.wakePipe.r)
+	var tv *syscall.Timeval
+	if timeout >= 0 {
+		t := syscall.NsecToTimeval(timeout.Nanoseconds())
+		tv = &t
+	}
+	err := syscall.Select(max(p.fd, p.wakePipe.r)+1, &set, nil, nil, tv)
+	if err == syscall.EINTR {
+		return false, nil
+	}
+	if err != nil {
+		return false, os.NewSyscallError("select", err)
+	}
+	if fdIsSet(&set, p.wakePipe.r) {
+		p.drain()
+	}
+	return fdIsSet(&set, p.fd), nil
+}
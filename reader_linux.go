@@ -0,0 +1,71 @@
+package terminal_go
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// ttyPoller waits for a terminal to become readable with epoll(7)
+type ttyPoller struct {
+	*wakePipe
+	fd   int
+	epfd int
+}
+
+func newTTYPoller(fd int) (*ttyPoller, error) {
+	epfd, err := syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, os.NewSyscallError("epoll_create1", err)
+	}
+	pipe, err := newWakePipe()
+	if err != nil {
+		syscall.Close(epfd)
+		return nil, err
+	}
+	p := &ttyPoller{wakePipe: pipe, fd: fd, epfd: epfd}
+	for _, watched := range []int{fd, pipe.r} {
+		ev := syscall.EpollEvent{Events: syscall.EPOLLIN, Fd: int32(watched)}
+		if err := syscall.EpollCtl(epfd, syscall.EPOLL_CTL_ADD, watched, &ev); err != nil {
+			p.close()
+			return nil, os.NewSyscallError("epoll_ctl", err)
+		}
+	}
+	return p, nil
+}
+
+// wait blocks until the terminal is readable, wake is called or timeout
+// passes, and reports whether the terminal is readable. A negative timeout
+// waits indefinitely
+func (p *ttyPoller) wait(timeout time.Duration) (bool, error) {
+	msec := -1
+	if timeout >= 0 {
+		msec = int((timeout + time.Millisecond - 1) / time.Millisecond)
+	}
+	var events [2]syscall.EpollEvent
+	n, err := syscall.EpollWait(p.epfd, events[:], msec)
+	if err == syscall.EINTR {
+		return false, nil
+	}
+	if err != nil {
+		return false, os.NewSyscallError("epoll_wait", err)
+	}
+	ready := false
+	for _, ev := range events[:n] {
+		if int(ev.Fd) == p.wakePipe.r {
+			p.drain()
+		} else {
+			// Errors and hang-ups are reported by the following read
+			ready = true
+		}
+	}
+	return ready, nil
+}
+
+func (p *ttyPoller) close() error {
+	err := p.wakePipe.close()
+	if err2 := syscall.Close(p.epfd); err == nil {
+		err = err2
+	}
+	return err
+}
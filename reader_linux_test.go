@@ -0,0 +1,77 @@
+package terminal_go
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPTY opens a pseudo-terminal pair, skipping the test where that is not possible
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	var unlock int32
+	var n uint32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		t.Skipf("unlockpt: %v", err)
+	}
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, unsafe.Pointer(&n)); err != nil {
+		t.Skipf("ptsname: %v", err)
+	}
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("open pts: %v", err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+func ioctl(fd uintptr, req uint, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// TestInputReaderResize verifies that SIGWINCH produces a ResizeEvent with the terminal's new size
+func TestInputReaderResize(t *testing.T) {
+	master, slave := openPTY(t)
+	ws := struct{ Row, Col, Xpixel, Ypixel uint16 }{Row: 40, Col: 132}
+	if err := ioctl(master.Fd(), syscall.TIOCSWINSZ, unsafe.Pointer(&ws)); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewInputReader(slave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The pty is not our controlling terminal, so send the signal ourselves
+	syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := r.ReadEvent(ctx)
+	if want := (ResizeEvent{Width: 132, Height: 40}); err != nil || got != want {
+		t.Errorf("ReadEvent() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// The pty is in canonical mode, so input arrives a line at a time
+	master.Write([]byte("q\n"))
+	got, err = r.ReadEvent(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k, ok := got.(KeyEvent); !ok || k.Rune != 'q' {
+		t.Errorf("ReadEvent() = %+v, want the q key", got)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !linux && !darwin
+
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// ttyPoller is not implemented on this platform
+type ttyPoller struct{}
+
+func newTTYPoller(fd int) (*ttyPoller, error) {
+	return nil, fmt.Errorf("terminal_go: input reader on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
+
+func (p *ttyPoller) wait(timeout time.Duration) (bool, error) { return false, errors.ErrUnsupported }
+func (p *ttyPoller) wake()                                    {}
+func (p *ttyPoller) close() error                             { return nil }
+
+func notifyResize(c chan<- os.Signal) {}
+func stopResize(c chan<- os.Signal)   {}
+
+func windowSize(fd int) (width, height int, err error) {
+	return 0, 0, errors.ErrUnsupported
+}
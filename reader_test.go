@@ -0,0 +1,174 @@
+//go:build linux || darwin
+
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newPipeReader creates an InputReader reading from a pipe in place of a terminal
+func newPipeReader(t *testing.T) (*InputReader, *os.File) {
+	t.Helper()
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		pr.Close()
+		pw.Close()
+	})
+	r, err := NewInputReader(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r, pw
+}
+
+// TestInputReaderReadEvent verifies that events are decoded from the terminal and a lone ESC times out
+func TestInputReaderReadEvent(t *testing.T) {
+	r, w := newPipeReader(t)
+	r.Decoder.EscapeTimeout = 10 * time.Millisecond
+	w.Write([]byte("a\033[1;5A\033[200~hi\033[201~\033"))
+
+	want := []Event{
+		KeyEvent{Key: KeyRune, Rune: 'a'},
+		KeyEvent{Key: KeyUp, Modifiers: ModCtrl},
+		PasteEvent{Text: "hi"},
+		KeyEvent{Key: KeyEscape},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, w := range want {
+		got, err := r.ReadEvent(ctx)
+		if err != nil || !reflect.DeepEqual(got, w) {
+			t.Fatalf("ReadEvent() = %+v, %v, want %+v, nil", got, err, w)
+		}
+	}
+
+	w.Close()
+	if got, err := r.ReadEvent(ctx); err != io.EOF {
+		t.Errorf("ReadEvent() after the writer closed = %+v, %v, want io.EOF", got, err)
+	}
+}
+
+// TestInputReaderCancel verifies that ReadEvent returns when its context is done and leaves no goroutine behind
+func TestInputReaderCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	r, w := newPipeReader(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := r.ReadEvent(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ReadEvent() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ReadEvent() took %v to notice the deadline", elapsed)
+	}
+
+	// The reader is still usable after a cancelled read
+	w.Write([]byte("x"))
+	got, err := r.ReadEvent(context.Background())
+	if err != nil || got != (KeyEvent{Key: KeyRune, Rune: 'x'}) {
+		t.Errorf("ReadEvent() after cancel = %+v, %v, want x", got, err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines after Close, want %d", n, before)
+	}
+}
+
+// TestInputReaderClose verifies that Close interrupts a ReadEvent in progress
+func TestInputReaderClose(t *testing.T) {
+	r, _ := newPipeReader(t)
+	done := make(chan error)
+	go func() {
+		_, err := r.ReadEvent(context.Background())
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != ErrReaderClosed {
+			t.Errorf("ReadEvent() = %v, want ErrReaderClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ReadEvent() did not return after Close")
+	}
+	if _, err := r.ReadEvent(context.Background()); err != ErrReaderClosed {
+		t.Errorf("ReadEvent() after Close = %v, want ErrReaderClosed", err)
+	}
+}
+
+// TestInputReaderEvents verifies delivery of events on a channel until the context is cancelled
+func TestInputReaderEvents(t *testing.T) {
+	r, w := newPipeReader(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := r.Events(ctx)
+
+	w.Write([]byte("\033[I\033[<0;3;4M"))
+	want := []Event{FocusInEvent{}, MouseEvent{X: 3, Y: 4, Button: MouseLeft}}
+	for _, w := range want {
+		select {
+		case got := <-events:
+			if got != w {
+				t.Errorf("event = %+v, want %+v", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event, want %+v", w)
+		}
+	}
+
+	cancel()
+	select {
+	case ev, ok := <-events:
+		if ok {
+			t.Errorf("event %+v after cancel, want closed channel", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+	if err := r.Err(); err != context.Canceled {
+		t.Errorf("Err() = %v, want context.Canceled", err)
+	}
+}
+
+// TestQueryBlockingFile verifies that Query can be cancelled on a terminal without read deadlines
+func TestQueryBlockingFile(t *testing.T) {
+	// A blocking socket stands in for a terminal: it can be written and
+	// read like one, and os.NewFile does not give it read deadlines
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tty := os.NewFile(uintptr(fds[0]), "tty")
+	term := os.NewFile(uintptr(fds[1]), "term")
+	defer tty.Close()
+	defer term.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := Query(ctx, tty, RequestCursorPosition()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Query() after cancel = %v, want context.Canceled", err)
+	}
+}
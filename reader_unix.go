@@ -0,0 +1,91 @@
+//go:build linux || darwin
+
+package terminal_go
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// notifyResize relays SIGWINCH, which the terminal sends when its window is resized, to c
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
+
+// stopResize undoes notifyResize
+func stopResize(c chan<- os.Signal) {
+	signal.Stop(c)
+}
+
+// windowSize returns the size of the terminal fd in columns and rows
+func windowSize(fd int) (width, height int, err error) {
+	var ws struct{ Row, Col, Xpixel, Ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// wakePipe lets another goroutine interrupt a poller waiting for input
+type wakePipe struct {
+	mu     sync.Mutex
+	r, w   int
+	closed bool
+}
+
+func newWakePipe() (*wakePipe, error) {
+	var p [2]int
+	syscall.ForkLock.RLock()
+	err := syscall.Pipe(p[:])
+	if err == nil {
+		syscall.CloseOnExec(p[0])
+		syscall.CloseOnExec(p[1])
+	}
+	syscall.ForkLock.RUnlock()
+	if err != nil {
+		return nil, os.NewSyscallError("pipe", err)
+	}
+	for _, fd := range p {
+		if err := syscall.SetNonblock(fd, true); err != nil {
+			syscall.Close(p[0])
+			syscall.Close(p[1])
+			return nil, os.NewSyscallError("setnonblock", err)
+		}
+	}
+	return &wakePipe{r: p[0], w: p[1]}, nil
+}
+
+// wake makes the read end readable. It is safe to call at any time, even after close
+func (p *wakePipe) wake() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		// A full pipe already wakes the poller, so EAGAIN is fine
+		syscall.Write(p.w, []byte{0})
+	}
+}
+
+// drain empties the read end after a wake-up
+func (p *wakePipe) drain() {
+	var buf [64]byte
+	for {
+		if n, err := syscall.Read(p.r, buf[:]); n <= 0 || err != nil {
+			return
+		}
+	}
+}
+
+func (p *wakePipe) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	err := syscall.Close(p.r)
+	if err2 := syscall.Close(p.w); err == nil {
+		err = err2
+	}
+	return err
+}
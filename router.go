@@ -0,0 +1,185 @@
+package terminal_go
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// InputRouter lets a program send queries to the terminal while an event
+// loop is reading its input. The terminal's replies arrive mixed with
+// the user's keys and some look like keys: the cursor position report
+// CSI 1 ; 2 R is also Shift+F3. The router hands the replies to the
+// goroutine that sent the query and returns everything else from ReadEvent.
+//
+// Replies are only routed while ReadEvent is being called, so Query must
+// not be called from the goroutine that runs the event loop
+type InputRouter struct {
+	reader *InputReader
+	tty    io.Writer
+	queue  []Event
+
+	// writeMu keeps queries in the order they were written, which is the
+	// order the terminal answers them in
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	queries []*routedQuery
+}
+
+// routedQuery is a query waiting for the terminal's reply
+type routedQuery struct {
+	// sentinels counts the Primary Device Attributes replies still to come;
+	// the query is answered after the last one
+	sentinels int
+	// cursorReports counts the cursor position reports still to come
+	cursorReports int
+	reply         []byte
+	// abandoned is set when the sender gave up, so its reply is discarded
+	abandoned bool
+	done      chan struct{}
+}
+
+// NewInputRouter creates a router that reads events from r and writes
+// queries to tty, the terminal r reads from. From then on, events should
+// only be read through the router
+func NewInputRouter(r *InputReader, tty io.Writer) *InputRouter {
+	rt := &InputRouter{reader: r, tty: tty}
+	r.Decoder.claimReply = rt.claimReply
+	return rt
+}
+
+// ReadEvent returns the next event that is not a reply to a query sent with
+// Query. It works like InputReader.ReadEvent
+func (rt *InputRouter) ReadEvent(ctx context.Context) (Event, error) {
+	for len(rt.queue) == 0 {
+		ev, err := rt.reader.ReadEvent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Route every reply read so far, so a query is answered even if
+		// keys decoded before its reply are not read yet
+		for _, ev := range append([]Event{ev}, rt.reader.takeQueued()...) {
+			if u, ok := ev.(UnknownEvent); ok && rt.route(u.Raw) {
+				continue
+			}
+			rt.queue = append(rt.queue, ev)
+		}
+	}
+	ev := rt.queue[0]
+	rt.queue = rt.queue[1:]
+	return ev, nil
+}
+
+// Query works like the function Query but takes the reply from the event
+// loop calling ReadEvent. Queries from several goroutines may be in flight
+// at once. If ctx is done first, the reply is discarded when it arrives
+func (rt *InputRouter) Query(ctx context.Context, request string) (string, error) {
+	q := &routedQuery{
+		sentinels:     countDA1Requests(request) + 1,
+		cursorReports: countCursorPositionRequests(request),
+		done:          make(chan struct{}),
+	}
+
+	rt.writeMu.Lock()
+	rt.mu.Lock()
+	rt.queries = append(rt.queries, q)
+	rt.mu.Unlock()
+	_, err := io.WriteString(rt.tty, request+RequestPrimaryDeviceAttributes)
+	if err != nil {
+		rt.mu.Lock()
+		rt.queries = rt.queries[:len(rt.queries)-1]
+		rt.mu.Unlock()
+	}
+	rt.writeMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	select {
+	case <-q.done:
+		if len(q.reply) == 0 {
+			return "", ErrNoReply
+		}
+		return string(q.reply), nil
+	case <-ctx.Done():
+		rt.mu.Lock()
+		defer rt.mu.Unlock()
+		q.abandoned = true
+		return "", ctx.Err()
+	}
+}
+
+// route passes a sequence to the oldest query waiting for a reply and
+// reports whether there was one. Sequences not shaped like a reply, such as
+// keys the decoder does not know, are left to the event loop
+func (rt *InputRouter) route(raw string) bool {
+	if !isReply(raw) {
+		return false
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(rt.queries) == 0 {
+		return false
+	}
+	q := rt.queries[0]
+	for _, tok := range DecodeString(raw) {
+		if isDA1Reply(tok) {
+			if q.sentinels--; q.sentinels == 0 {
+				rt.queries = rt.queries[1:]
+				close(q.done)
+				return true
+			}
+		}
+	}
+	if !q.abandoned {
+		q.reply = append(q.reply, raw...)
+	}
+	return true
+}
+
+// isReply reports whether an unrecognized input sequence is shaped like a
+// reply to a query: a string, or a control sequence with a private marker
+// or intermediates. A cursor position report only arrives unrecognized
+// once claimReply has taken it for a query
+func isReply(raw string) bool {
+	toks := DecodeString(raw)
+	if len(toks) != 1 {
+		return false
+	}
+	a := toks[0].Action
+	switch toks[0].Type {
+	case TokenOSC, TokenDCS, TokenString:
+		return true
+	case TokenCSI:
+		return a.Private != 0 || a.Intermediates != "" || a.Final == 'R' && len(a.Params) == 2
+	}
+	return false
+}
+
+// claimReply tells the decoder whether a sequence is a cursor position
+// report for a pending query rather than a key
+func (rt *InputRouter) claimReply(a Action) bool {
+	if a.Private != 0 || a.Intermediates != "" || a.Final != 'R' || len(a.Params) != 2 {
+		return false
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, q := range rt.queries {
+		if q.cursorReports > 0 {
+			q.cursorReports--
+			return true
+		}
+	}
+	return false
+}
+
+// countCursorPositionRequests counts the cursor position requests (DSR 6) in s
+func countCursorPositionRequests(s string) int {
+	n := 0
+	for _, a := range ParseString(s) {
+		if a.Type == ActionCSIDispatch && a.Private == 0 && a.Intermediates == "" && a.Final == 'n' && a.Param(0, 0) == 6 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+//go:build linux || darwin
+
+package terminal_go
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// answerFunc is a fake terminal's output side: writing a request to it calls the function
+type answerFunc func(request string)
+
+func (f answerFunc) Write(p []byte) (int, error) {
+	f(string(p))
+	return len(p), nil
+}
+
+// TestInputRouter verifies that replies go to Query while keys, including
+// ones typed in the middle of a reply, reach the event loop
+func TestInputRouter(t *testing.T) {
+	r, w := newPipeReader(t)
+	rt := NewInputRouter(r, answerFunc(func(request string) {
+		switch request {
+		case RequestCursorPosition() + RequestPrimaryDeviceAttributes:
+			// A kitty media key and an unknown key arrive amid the reply
+			w.Write([]byte("\033[1;2Ra\033[57428u\033[99~\033[?62;22c"))
+		case QueryModifyOtherKeys + RequestPrimaryDeviceAttributes:
+			w.Write([]byte("\033[?62;22c"))
+		}
+		// Shift+F3 after the reply is a key again
+		w.Write([]byte("\033[1;2R"))
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	type result struct {
+		reply string
+		err   error
+	}
+	results := make(chan result)
+	go func() {
+		reply, err := rt.Query(ctx, RequestCursorPosition())
+		results <- result{reply, err}
+		reply, err = rt.Query(ctx, QueryModifyOtherKeys)
+		results <- result{reply, err}
+	}()
+
+	want := []Event{
+		KeyEvent{Key: KeyRune, Rune: 'a'},
+		UnknownEvent{Raw: "\033[57428u"},
+		UnknownEvent{Raw: "\033[99~"},
+		KeyEvent{Key: KeyF3, Modifiers: ModShift},
+		KeyEvent{Key: KeyF3, Modifiers: ModShift},
+	}
+	for _, w := range want {
+		got, err := rt.ReadEvent(ctx)
+		if err != nil || !reflect.DeepEqual(got, w) {
+			t.Fatalf("ReadEvent() = %+v, %v, want %+v, nil", got, err, w)
+		}
+		if w == want[0] {
+			if res := <-results; res.err != nil || res.reply != "\033[1;2R" {
+				t.Errorf("Query(CPR) = %q, %v, want %q, nil", res.reply, res.err, "\033[1;2R")
+			}
+		}
+	}
+	if res := <-results; !errors.Is(res.err, ErrNoReply) {
+		t.Errorf("Query(unsupported) = %q, %v, want ErrNoReply", res.reply, res.err)
+	}
+}
+
+// TestInputRouterAbandoned verifies that the reply to a query whose context
+// ended is still kept from the event loop
+func TestInputRouterAbandoned(t *testing.T) {
+	r, w := newPipeReader(t)
+	rt := NewInputRouter(r, answerFunc(func(string) {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := rt.Query(ctx, RequestCursorPosition()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Query() = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The late reply is dropped
+	w.Write([]byte("\033[5;10R\033[?62;22cx"))
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if got, err := rt.ReadEvent(ctx); err != nil || got != (KeyEvent{Key: KeyRune, Rune: 'x'}) {
+		t.Errorf("ReadEvent() = %+v, %v, want x", got, err)
+	}
+}
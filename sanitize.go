@@ -0,0 +1,152 @@
+package terminal_go
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode selects what happens to a sequence that a SanitizePolicy does not allow
+type SanitizeMode int
+
+const (
+	// SanitizeStrip removes disallowed sequences and controls
+	SanitizeStrip SanitizeMode = iota
+	// SanitizeEscape replaces disallowed sequences and controls with a visible
+	// rendering in caret notation, e.g. "^[]52;c;...^G"
+	SanitizeEscape
+)
+
+// SanitizePolicy decides which sequences in untrusted text, such as CI logs,
+// user names or file contents, are passed to the terminal.
+// Every family is an allow-list; anything not listed is stripped or escaped.
+type SanitizePolicy struct {
+	// Mode selects whether disallowed sequences are stripped or escaped
+	Mode SanitizeMode
+	// Controls lists the C0 and C1 control characters that are passed through
+	Controls string
+	// CSI lists the final bytes of control sequences that are passed through.
+	// Sequences with a private marker or intermediate bytes are never allowed,
+	// so allowing 'm' permits SGR styling but not XTMODKEYS (CSI > 4 ; 2 m)
+	CSI string
+	// ESC lists the final bytes of escape sequences without intermediate bytes that are passed through
+	ESC string
+	// OSC lists the operating system commands that are passed through, e.g. 8 for hyperlinks.
+	// OSC queries such as "OSC 11 ; ?" are never allowed
+	OSC []int
+	// DCS lists the final bytes of device control strings that are passed through.
+	// Strings with a private marker or intermediate bytes are never allowed,
+	// so allowing 'q' permits sixel graphics but not DECRQSS (DCS $ q) or
+	// XTGETTCAP (DCS + q)
+	DCS string
+	// Strings lists the introducers of SOS ('X'), PM ('^') and APC ('_') strings that are passed through
+	Strings string
+}
+
+// DefaultSanitizePolicy returns a policy that keeps SGR styling, tabs and
+// line breaks and strips everything else. In particular it removes
+// sequences that set the clipboard (OSC 52) or window title, redefine keys,
+// write to the printer, download soft fonts or query the terminal.
+func DefaultSanitizePolicy() SanitizePolicy {
+	return SanitizePolicy{
+		Mode:     SanitizeStrip,
+		Controls: "\t\n\r",
+		CSI:      "m",
+	}
+}
+
+// Sanitize applies DefaultSanitizePolicy to s
+func Sanitize(s string) string {
+	return DefaultSanitizePolicy().Sanitize(s)
+}
+
+// Sanitize applies the policy to a complete string
+func (p SanitizePolicy) Sanitize(s string) string {
+	var buf []byte
+	for _, tok := range DecodeString(s) {
+		buf = p.appendToken(buf, tok)
+	}
+	return string(buf)
+}
+
+// Allows reports whether the policy passes tok through unchanged
+func (p SanitizePolicy) Allows(tok Token) bool {
+	if tok.Truncated {
+		return false
+	}
+	a := tok.Action
+	switch tok.Type {
+	case TokenText:
+		return true
+	case TokenControl:
+		return strings.ContainsRune(p.Controls, a.Rune)
+	case TokenESC:
+		return a.Intermediates == "" && strings.IndexByte(p.ESC, a.Final) >= 0
+	case TokenCSI:
+		return a.Private == 0 && a.Intermediates == "" && strings.IndexByte(p.CSI, a.Final) >= 0
+	case TokenOSC:
+		command, ok := oscCommand(a.Data)
+		return ok && slices.Contains(p.OSC, command) && !isOSCQuery(a.Data)
+	case TokenDCS:
+		return a.Private == 0 && a.Intermediates == "" && strings.IndexByte(p.DCS, a.Final) >= 0
+	case TokenString:
+		return strings.IndexByte(p.Strings, a.Final) >= 0
+	}
+	return false
+}
+
+func (p SanitizePolicy) appendToken(buf []byte, tok Token) []byte {
+	switch {
+	case tok.Type == TokenText:
+		return append(buf, tok.Text...)
+	case p.Allows(tok):
+		return append(buf, tok.Raw...)
+	case p.Mode == SanitizeEscape:
+		return appendCaretNotation(buf, tok.Raw)
+	}
+	return buf
+}
+
+// oscCommand returns the numeric command at the start of an OSC payload
+func oscCommand(data string) (int, bool) {
+	command, _, _ := strings.Cut(data, ";")
+	n, err := strconv.Atoi(command)
+	return n, err == nil
+}
+
+// isOSCQuery reports whether any parameter of an OSC payload is "?"
+func isOSCQuery(data string) bool {
+	return slices.Contains(strings.Split(data, ";"), "?")
+}
+
+// appendCaretNotation appends s with control characters made visible:
+// C0 controls as ^@ to ^_, DEL as ^? and C1 controls as \u0080 to \u009f
+func appendCaretNotation(buf []byte, s string) []byte {
+	for _, r := range s {
+		switch {
+		case r < 0x20:
+			buf = append(buf, '^', byte(r)+0x40)
+		case r == 0x7F:
+			buf = append(buf, '^', '?')
+		case r >= 0x80 && r < 0xA0:
+			buf = fmt.Appendf(buf, "\\u%04x", r)
+		default:
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return buf
+}
+
+// SanitizeWriter is an io.Writer that applies a SanitizePolicy to everything
+// written to it before passing it on. Call Flush when the stream ends.
+type SanitizeWriter struct {
+	filterWriter
+}
+
+// NewSanitizeWriter creates a SanitizeWriter that writes the output of policy to w
+func NewSanitizeWriter(w io.Writer, policy SanitizePolicy) *SanitizeWriter {
+	return &SanitizeWriter{filterWriter{w: w, render: policy.appendToken}}
+}
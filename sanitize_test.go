@@ -0,0 +1,128 @@
+package terminal_go
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestSanitize verifies that the default policy keeps styling and removes dangerous sequences
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain text", "hello\tworld\r\n", "hello\tworld\r\n"},
+		{"SGR styling", SetTextColor(1) + "red" + ResetAllAttributes, SetTextColor(1) + "red" + ResetAllAttributes},
+		{"RGB styling", SetRGBBackgroundColor(1, 2, 3) + "bg", SetRGBBackgroundColor(1, 2, 3) + "bg"},
+		{"Clipboard", "a\033]52;c;cm0gLXJmIC8=\ab", "ab"},
+		{"Title", "\033]0;pwned\033\\name", "name"},
+		{"Hyperlink", "\033]8;;https://evil.example\033\\click\033]8;;\033\\", "click"},
+		{"Key redefinition", "\033P0;1|17/2F\033\\x", "x"},
+		{"Modify other keys", "\033[>4;2mx", "x"},
+		{"Printer", "\033[5ix\033[4i", "x"},
+		{"Soft font", "\033P1;1;1{ @???\033\\x", "x"},
+		{"Queries", RequestCursorPosition() + "\033[c\033P$qm\033\\\033]11;?\a" + "x", "x"},
+		{"Cursor movement", CursorPosition(1, 1) + EraseInDisplay + "x", "x"},
+		{"Alternate screen", EnterAltScreen + "x", "x"},
+		{"Charset switch", DesignateCharacterSet(0, '0') + "x", "x"},
+		{"APC", "\033_Gf=100;AAAA\033\\x", "x"},
+		{"Controls", "a\a\b\x00b", "ab"},
+		{"C1 CSI", "a\u009b2Jb", "a2Jb"},
+	}
+
+	for _, tt := range tests {
+		if got := Sanitize(tt.input); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestSanitizePolicy verifies custom allow-lists and escaping of disallowed sequences
+func TestSanitizePolicy(t *testing.T) {
+	policy := SanitizePolicy{
+		Mode:     SanitizeEscape,
+		Controls: "\n",
+		CSI:      "mHK",
+		ESC:      "78",
+		OSC:      []int{8, 11},
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Allowed CSI", CursorPosition(2, 3) + EraseInLine, CursorPosition(2, 3) + EraseInLine},
+		{"Allowed ESC", SaveCursorPointerInMemory + RestoreCursorPointerFromMemory, SaveCursorPointerInMemory + RestoreCursorPointerFromMemory},
+		{"Allowed OSC", "\033]8;;https://example.com\033\\", "\033]8;;https://example.com\033\\"},
+		{"OSC query is never allowed", "\033]11;?\a", "^[]11;?^G"},
+		{"Escaped OSC", "\033]52;c;YQ==\a", "^[]52;c;YQ==^G"},
+		{"Escaped private CSI", EnterAltScreen, "^[[?1049h"},
+		{"Escaped control", "a\bb", "a^Hb"},
+		{"Escaped C1", "\u009b", "\\u009b"},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Sanitize(tt.input); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestSanitizePolicyDCS verifies that allowing sixel graphics does not let DCS queries through
+func TestSanitizePolicyDCS(t *testing.T) {
+	policy := SanitizePolicy{DCS: "q"}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Allowed sixel", "\033P0;1q#0;2;0;0;0\033\\", "\033P0;1q#0;2;0;0;0\033\\"},
+		{"DECRQSS is stripped", "a" + RequestStatusString(StatusGraphicsRendition) + "b", "ab"},
+		{"XTGETTCAP is stripped", "a" + RequestCapabilities("Co") + "b", "ab"},
+	}
+	for _, tt := range tests {
+		if got := policy.Sanitize(tt.input); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestSanitizeWriter verifies that SanitizeWriter handles sequences split across writes
+func TestSanitizeWriter(t *testing.T) {
+	input := BoldBright + "name" + NormalIntensity + "\033]52;c;YQ==\a" + "\033]0;title\033\\!"
+	want := BoldBright + "name" + NormalIntensity + "!"
+
+	for size := 1; size <= len(input); size++ {
+		var buf bytes.Buffer
+		w := NewSanitizeWriter(&buf, DefaultSanitizePolicy())
+		for i := 0; i < len(input); i += size {
+			if _, err := w.Write([]byte(input[i:min(i+size, len(input))])); err != nil {
+				t.Fatalf("Write() = %v", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush() = %v", err)
+		}
+		if buf.String() != want {
+			t.Errorf("chunk size %d: got %q, want %q", size, buf.String(), want)
+		}
+	}
+}
+
+// ExampleSanitize demonstrates displaying a remote user name safely
+func ExampleSanitize() {
+	name := SetTextColor(2) + "alice" + ResetAllAttributes + "\033]52;c;cm0gLXJmIC8=\a"
+	fmt.Println("Hello,", Sanitize(name))
+}
+
+// ExampleSanitizeWriter demonstrates copying untrusted CI output to the terminal
+func ExampleSanitizeWriter() {
+	policy := DefaultSanitizePolicy()
+	policy.OSC = append(policy.OSC, 8) // Keep hyperlinks
+	w := NewSanitizeWriter(os.Stdout, policy)
+	fmt.Fprint(w, "\033]0;new title\aBuild passed\n")
+	w.Flush()
+}
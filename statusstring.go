@@ -0,0 +1,315 @@
+package terminal_go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStatusNotRecognized is returned when the terminal replies to a status
+// string request that it does not recognize the setting
+var ErrStatusNotRecognized = errors.New("terminal_go: status string request not recognized")
+
+// StatusSetting names a setting for RequestStatusString by the intermediate
+// and final bytes of the control function that changes it
+type StatusSetting string
+
+const (
+	// StatusGraphicsRendition is the current SGR, as set by SetGraphicsRendition
+	StatusGraphicsRendition StatusSetting = "m"
+	// StatusScrollingRegion is the top and bottom margins (DECSTBM), as set by SetScrollingRegion
+	StatusScrollingRegion StatusSetting = "r"
+	// StatusHorizontalMargins is the left and right margins (DECSLRM)
+	StatusHorizontalMargins StatusSetting = "s"
+	// StatusCursorStyle is the cursor shape and blinking (DECSCUSR)
+	StatusCursorStyle StatusSetting = " q"
+	// StatusConformanceLevel is the conformance level (DECSCL), as set by SetConformanceLevel
+	StatusConformanceLevel StatusSetting = "\"p"
+	// StatusCharacterProtection is the character protection attribute (DECSCA)
+	StatusCharacterProtection StatusSetting = "\"q"
+)
+
+var statusSettingNames = map[StatusSetting]string{
+	StatusGraphicsRendition:   "SGR",
+	StatusScrollingRegion:     "DECSTBM",
+	StatusHorizontalMargins:   "DECSLRM",
+	StatusCursorStyle:         "DECSCUSR",
+	StatusConformanceLevel:    "DECSCL",
+	StatusCharacterProtection: "DECSCA",
+}
+
+// String returns the DEC mnemonic of the setting, e.g. "DECSTBM"
+func (s StatusSetting) String() string {
+	if name, ok := statusSettingNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("%q", string(s))
+}
+
+// RequestStatusString (DECRQSS) asks the terminal for the current value of
+// a setting. The reply is DCS 1 $ r followed by the control function that
+// would restore the setting; the ParseStatus functions decode it
+func RequestStatusString(setting StatusSetting) string {
+	return "\033P$q" + string(setting) + "\033\\"
+}
+
+// ReportStatusString (DECRPSS) formats the reply to RequestStatusString.
+// value is the control function without its CSI, e.g. "1;24r"
+func ReportStatusString(value string) string {
+	return "\033P1$r" + value + "\033\\"
+}
+
+// ParseRequestStatusString parses a sequence produced by RequestStatusString
+func ParseRequestStatusString(s string) (StatusSetting, error) {
+	a, err := sequenceSpec{name: "RequestStatusString", typ: ActionDCSHook, intermediates: "$", final: 'q'}.parse(s)
+	if err != nil {
+		return "", err
+	}
+	return StatusSetting(a.Data), nil
+}
+
+// ParseStatusString parses the reply to RequestStatusString and returns the
+// control function it holds without its CSI, e.g. "1;24r". It returns
+// ErrStatusNotRecognized if the terminal did not recognize the request
+func ParseStatusString(s string) (string, error) {
+	spec := sequenceSpec{name: "StatusString", typ: ActionDCSHook, intermediates: "$", final: 'r', maxParams: 1}
+	a, err := spec.parse(s)
+	if err != nil {
+		return "", err
+	}
+	// DEC terminals answered 0 for valid requests, but xterm and its
+	// successors answer 1 and use 0 for invalid ones
+	switch a.Param(0, OmittedParam) {
+	case 0:
+		return "", ErrStatusNotRecognized
+	case 1:
+		return a.Data, nil
+	}
+	return "", spec.error(s)
+}
+
+// parseStatus parses the reply to RequestStatusString for setting, whose
+// control function takes at most maxParams parameters
+func parseStatus(s, name string, setting StatusSetting, maxParams int) (Action, error) {
+	value, err := ParseStatusString(s)
+	if err != nil {
+		return Action{}, err
+	}
+	n := len(setting) - 1
+	spec := sequenceSpec{name: name, typ: ActionCSIDispatch, intermediates: string(setting[:n]), final: setting[n], maxParams: maxParams}
+	a, err := spec.parse("\033[" + value)
+	if err != nil {
+		return Action{}, spec.error(s)
+	}
+	return a, nil
+}
+
+// ColorKind tells how an SGRColor is specified
+type ColorKind int
+
+const (
+	// ColorDefault is the terminal's default color
+	ColorDefault ColorKind = iota
+	// ColorIndexed is an entry of the 256-color palette. The basic colors
+	// 30-37 and 40-47 are entries 0-7, their bright forms entries 8-15
+	ColorIndexed
+	// ColorRGB is a 24-bit color
+	ColorRGB
+)
+
+// SGRColor is a foreground or background color selected by SGR
+type SGRColor struct {
+	Kind ColorKind
+	// Index is the palette entry of a ColorIndexed color
+	Index int
+	// RGB is the value of a ColorRGB color
+	RGB RGBColor
+}
+
+// GraphicsRendition is the state of the text attributes set by SGR
+type GraphicsRendition struct {
+	Bold, Faint, Italic, Underline, Blink, Inverse, Invisible, CrossedOut bool
+	Foreground, Background                                                SGRColor
+}
+
+// ParseStatusGraphicsRendition parses the reply to a request for
+// StatusGraphicsRendition. Colors may use either the semicolon form,
+// 38;2;R;G;B, or the colon form, 38:2::R:G:B. Attributes the result has no
+// field for, such as overline, are ignored
+func ParseStatusGraphicsRendition(s string) (GraphicsRendition, error) {
+	spec := sequenceSpec{name: "StatusGraphicsRendition"}
+	value, err := ParseStatusString(s)
+	if err != nil {
+		return GraphicsRendition{}, err
+	}
+	actions := ParseString("\033[" + value)
+	if len(actions) != 1 || actions[0].Type != ActionCSIDispatch || actions[0].Private != 0 ||
+		actions[0].Intermediates != "" || actions[0].Final != 'm' {
+		return GraphicsRendition{}, spec.error(s)
+	}
+
+	a := actions[0]
+	var r GraphicsRendition
+	for i := 0; i < len(a.Params); i++ {
+		switch p := a.Param(i, 0); {
+		case p == 0:
+			r = GraphicsRendition{}
+		case p == 1:
+			r.Bold = true
+		case p == 2:
+			r.Faint = true
+		case p == 3:
+			r.Italic = true
+		case p == 4:
+			// 4:0 turns underline off, 4:1 to 4:5 select its style
+			r.Underline = a.Params[i].Sub(0, 1) != 0
+		case p == 5 || p == 6:
+			r.Blink = true
+		case p == 7:
+			r.Inverse = true
+		case p == 8:
+			r.Invisible = true
+		case p == 9:
+			r.CrossedOut = true
+		case p == 21:
+			r.Underline = true
+		case p == 22:
+			r.Bold, r.Faint = false, false
+		case p == 23:
+			r.Italic = false
+		case p == 24:
+			r.Underline = false
+		case p == 25:
+			r.Blink = false
+		case p == 27:
+			r.Inverse = false
+		case p == 28:
+			r.Invisible = false
+		case p == 29:
+			r.CrossedOut = false
+		case p >= 30 && p <= 37:
+			r.Foreground = SGRColor{Kind: ColorIndexed, Index: p - 30}
+		case p >= 40 && p <= 47:
+			r.Background = SGRColor{Kind: ColorIndexed, Index: p - 40}
+		case p >= 90 && p <= 97:
+			r.Foreground = SGRColor{Kind: ColorIndexed, Index: p - 90 + 8}
+		case p >= 100 && p <= 107:
+			r.Background = SGRColor{Kind: ColorIndexed, Index: p - 100 + 8}
+		case p == 39:
+			r.Foreground = SGRColor{}
+		case p == 49:
+			r.Background = SGRColor{}
+		case p == 38 || p == 48 || p == 58:
+			// 58 is the underline color, which is parsed only to skip it
+			c, n, ok := parseSGRColor(a, i)
+			if !ok {
+				return GraphicsRendition{}, spec.error(s)
+			}
+			i += n
+			if p == 38 {
+				r.Foreground = c
+			} else if p == 48 {
+				r.Background = c
+			}
+		}
+	}
+	return r, nil
+}
+
+// parseSGRColor parses the extended color selected by the i-th parameter of
+// a, 38, 48 or 58, and returns how many of the following parameters it spans
+func parseSGRColor(a Action, i int) (c SGRColor, n int, ok bool) {
+	var mode int
+	var values []int
+	if p := a.Params[i]; len(p) > 1 {
+		// Colon form: 38:5:I, 38:2:CS:R:G:B, or 38:2:R:G:B without the
+		// color space identifier
+		mode = p.Sub(0, 0)
+		for j := 1; j < len(p)-1; j++ {
+			values = append(values, p.Sub(j, 0))
+		}
+		if mode == 2 && len(values) == 4 {
+			values = values[1:]
+		}
+	} else {
+		mode = a.Param(i+1, 0)
+		switch mode {
+		case 5:
+			n = 2
+		case 2:
+			n = 4
+		}
+		if i+n >= len(a.Params) {
+			return SGRColor{}, 0, false
+		}
+		for j := i + 2; j <= i+n; j++ {
+			values = append(values, a.Param(j, 0))
+		}
+	}
+
+	for _, v := range values {
+		if v < 0 || v > 255 {
+			return SGRColor{}, 0, false
+		}
+	}
+	switch {
+	case mode == 5 && len(values) == 1:
+		return SGRColor{Kind: ColorIndexed, Index: values[0]}, n, true
+	case mode == 2 && len(values) == 3:
+		return SGRColor{Kind: ColorRGB, RGB: RGBColor{R: values[0], G: values[1], B: values[2]}}, n, true
+	}
+	return SGRColor{}, 0, false
+}
+
+// ParseStatusScrollingRegion parses the reply to a request for
+// StatusScrollingRegion. An omitted bottom margin defaults to 0
+func ParseStatusScrollingRegion(s string) (top, bottom int, err error) {
+	a, err := parseStatus(s, "StatusScrollingRegion", StatusScrollingRegion, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Param(0, 1), a.Param(1, 0), nil
+}
+
+// ParseStatusHorizontalMargins parses the reply to a request for
+// StatusHorizontalMargins. An omitted right margin defaults to 0
+func ParseStatusHorizontalMargins(s string) (left, right int, err error) {
+	a, err := parseStatus(s, "StatusHorizontalMargins", StatusHorizontalMargins, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Param(0, 1), a.Param(1, 0), nil
+}
+
+// ParseStatusCursorStyle parses the reply to a request for StatusCursorStyle
+// into the DECSCUSR style: 1 and 2 are a blinking and steady block, 3 and
+// 4 an underline, and 5 and 6 a bar
+func ParseStatusCursorStyle(s string) (style int, err error) {
+	a, err := parseStatus(s, "StatusCursorStyle", StatusCursorStyle, 1)
+	if err != nil {
+		return 0, err
+	}
+	return a.Param(0, 1), nil
+}
+
+// ParseStatusConformanceLevel parses the reply to a request for
+// StatusConformanceLevel. level is the DECSCL parameter, e.g. 64 for VT400
+// mode, and eightBitControls reports whether the terminal sends C1 controls
+// as single bytes
+func ParseStatusConformanceLevel(s string) (level int, eightBitControls bool, err error) {
+	a, err := parseStatus(s, "StatusConformanceLevel", StatusConformanceLevel, 2)
+	if err != nil {
+		return 0, false, err
+	}
+	return a.Param(0, 0), a.Param(1, 0) != 1, nil
+}
+
+// ParseStatusCharacterProtection parses the reply to a request for
+// StatusCharacterProtection and reports whether newly written characters
+// are protected from selective erase
+func ParseStatusCharacterProtection(s string) (protected bool, err error) {
+	a, err := parseStatus(s, "StatusCharacterProtection", StatusCharacterProtection, 1)
+	if err != nil {
+		return false, err
+	}
+	return a.Param(0, 0) == 1, nil
+}
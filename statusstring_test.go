@@ -0,0 +1,122 @@
+package terminal_go
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRequestStatusString verifies the DECRQSS emitter and its parser
+func TestRequestStatusString(t *testing.T) {
+	settings := []StatusSetting{
+		StatusGraphicsRendition, StatusScrollingRegion, StatusHorizontalMargins,
+		StatusCursorStyle, StatusConformanceLevel, StatusCharacterProtection,
+	}
+	for _, setting := range settings {
+		seq := RequestStatusString(setting)
+		if want := "\033P$q" + string(setting) + "\033\\"; seq != want {
+			t.Errorf("RequestStatusString(%v) = %q, want %q", setting, seq, want)
+		}
+		if got, err := ParseRequestStatusString(seq); err != nil || got != setting {
+			t.Errorf("ParseRequestStatusString(%q) = %q, %v, want %q, nil", seq, got, err, setting)
+		}
+	}
+	if got := StatusCursorStyle.String(); got != "DECSCUSR" {
+		t.Errorf("StatusCursorStyle.String() = %q, want DECSCUSR", got)
+	}
+}
+
+// TestParseStatusString verifies decoding of DECRPSS replies
+func TestParseStatusString(t *testing.T) {
+	if got, err := ParseStatusString(ReportStatusString("1;24r")); err != nil || got != "1;24r" {
+		t.Errorf("ParseStatusString() = %q, %v, want %q, nil", got, err, "1;24r")
+	}
+	if _, err := ParseStatusString("\033P0$r\033\\"); !errors.Is(err, ErrStatusNotRecognized) {
+		t.Errorf("ParseStatusString(invalid request) error = %v, want ErrStatusNotRecognized", err)
+	}
+	for _, s := range []string{RequestStatusString(StatusGraphicsRendition), "\033P2$rm\033\\", "\033P$rm\033\\", "\033[1;24r"} {
+		if _, err := ParseStatusString(s); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseStatusString(%q) error = %v, want ErrUnexpectedSequence", s, err)
+		}
+	}
+
+	if top, bottom, err := ParseStatusScrollingRegion(ReportStatusString("2;24r")); err != nil || top != 2 || bottom != 24 {
+		t.Errorf("ParseStatusScrollingRegion() = %d, %d, %v, want 2, 24, nil", top, bottom, err)
+	}
+	if left, right, err := ParseStatusHorizontalMargins(ReportStatusString("1;80s")); err != nil || left != 1 || right != 80 {
+		t.Errorf("ParseStatusHorizontalMargins() = %d, %d, %v, want 1, 80, nil", left, right, err)
+	}
+	if style, err := ParseStatusCursorStyle(ReportStatusString("6 q")); err != nil || style != 6 {
+		t.Errorf("ParseStatusCursorStyle() = %d, %v, want 6, nil", style, err)
+	}
+	if level, eightBit, err := ParseStatusConformanceLevel(ReportStatusString("64;1\"p")); err != nil || level != 64 || eightBit {
+		t.Errorf("ParseStatusConformanceLevel() = %d, %v, %v, want 64, false, nil", level, eightBit, err)
+	}
+	if protected, err := ParseStatusCharacterProtection(ReportStatusString("1\"q")); err != nil || !protected {
+		t.Errorf("ParseStatusCharacterProtection() = %v, %v, want true, nil", protected, err)
+	}
+
+	// A reply for another setting is rejected
+	if _, _, err := ParseStatusScrollingRegion(ReportStatusString("1;80s")); !errors.Is(err, ErrUnexpectedSequence) {
+		t.Errorf("ParseStatusScrollingRegion(DECSLRM reply) error = %v, want ErrUnexpectedSequence", err)
+	}
+	if _, err := ParseStatusGraphicsRendition(ReportStatusString("6 q")); !errors.Is(err, ErrUnexpectedSequence) {
+		t.Errorf("ParseStatusGraphicsRendition(DECSCUSR reply) error = %v, want ErrUnexpectedSequence", err)
+	}
+	if _, err := ParseStatusCursorStyle("\033P0$r\033\\"); !errors.Is(err, ErrStatusNotRecognized) {
+		t.Errorf("ParseStatusCursorStyle(invalid request) error = %v, want ErrStatusNotRecognized", err)
+	}
+}
+
+// TestParseStatusGraphicsRendition verifies decoding of SGR replies with
+// colors in both the semicolon and the colon form
+func TestParseStatusGraphicsRendition(t *testing.T) {
+	orange := SGRColor{Kind: ColorRGB, RGB: RGBColor{R: 255, G: 128}}
+	tests := []struct {
+		value string
+		want  GraphicsRendition
+	}{
+		{"0m", GraphicsRendition{}},
+		{"m", GraphicsRendition{}},
+		{"0;1;3;4;7;9m", GraphicsRendition{Bold: true, Italic: true, Underline: true, Inverse: true, CrossedOut: true}},
+		{"2;5;8;1;22m", GraphicsRendition{Blink: true, Invisible: true}},
+		{"4:3m", GraphicsRendition{Underline: true}},
+		{"4;4:0m", GraphicsRendition{}},
+		{"31;102m", GraphicsRendition{Foreground: SGRColor{Kind: ColorIndexed, Index: 1}, Background: SGRColor{Kind: ColorIndexed, Index: 10}}},
+		// Semicolon form
+		{"38;5;208;48;2;255;128;0m", GraphicsRendition{Foreground: SGRColor{Kind: ColorIndexed, Index: 208}, Background: orange}},
+		{"38;2;255;128;0;1m", GraphicsRendition{Bold: true, Foreground: orange}},
+		// Colon form, with and without the color space identifier
+		{"38:2::255:128:0;48:5:17m", GraphicsRendition{Foreground: orange, Background: SGRColor{Kind: ColorIndexed, Index: 17}}},
+		{"48:2:255:128:0m", GraphicsRendition{Background: orange}},
+		// The underline color is skipped, and 39 restores the default
+		{"58;2;1;2;3;31;39;53m", GraphicsRendition{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseStatusGraphicsRendition(ReportStatusString(tt.value))
+		if err != nil || got != tt.want {
+			t.Errorf("ParseStatusGraphicsRendition(%q) = %+v, %v, want %+v, nil", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"38;5m", "38;2;1;2m", "38;3;1m", "38:5:256m", "48;2;1;2;300m"} {
+		if _, err := ParseStatusGraphicsRendition(ReportStatusString(value)); !errors.Is(err, ErrUnexpectedSequence) {
+			t.Errorf("ParseStatusGraphicsRendition(%q) error = %v, want ErrUnexpectedSequence", value, err)
+		}
+	}
+}
+
+// TestDescribeStatusString verifies explanations of DECRQSS and DECRPSS
+func TestDescribeStatusString(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{RequestStatusString(StatusScrollingRegion), `DECRQSS — request setting "r"`},
+		{ReportStatusString("1;24r"), `DECRPSS — setting report "1;24r"`},
+		{"\033P0$r\033\\", "DECRPSS — setting report: request not recognized"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.seq).String(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
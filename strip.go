@@ -0,0 +1,100 @@
+package terminal_go
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// isPlainWhitespace reports whether r is a control character that StripANSI keeps
+func isPlainWhitespace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// appendPlainText appends the printable text and whitespace of tok to buf
+func appendPlainText(buf []byte, tok Token) []byte {
+	switch tok.Type {
+	case TokenText:
+		return append(buf, tok.Text...)
+	case TokenControl:
+		if isPlainWhitespace(tok.Action.Rune) {
+			return utf8.AppendRune(buf, tok.Action.Rune)
+		}
+	}
+	return buf
+}
+
+// isPlainText reports whether s contains nothing for StripANSI to remove
+func isPlainText(s string) bool {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if (b < 0x20 && !isPlainWhitespace(rune(b))) || b == 0x7F || b == 0xC2 {
+			return false
+		}
+	}
+	return utf8.ValidString(s)
+}
+
+// StripANSI removes escape sequences (CSI, OSC, DCS, SOS, PM and APC) and
+// C0 and C1 control characters from s. Printable text, tabs, line breaks
+// and carriage returns are kept. Invalid UTF-8 is replaced by U+FFFD.
+func StripANSI(s string) string {
+	if isPlainText(s) {
+		return s
+	}
+	var buf []byte
+	for _, tok := range DecodeString(s) {
+		buf = appendPlainText(buf, tok)
+	}
+	return string(buf)
+}
+
+// filterWriter decodes everything written to it and writes what render
+// makes of each token to the underlying writer
+type filterWriter struct {
+	w      io.Writer
+	d      Decoder
+	buf    []byte
+	render func([]byte, Token) []byte
+}
+
+// Write filters p and writes the result to the underlying writer.
+// It reports len(p) bytes written unless the underlying writer fails
+func (f *filterWriter) Write(p []byte) (int, error) {
+	if err := f.write(f.d.Feed(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any trailing partial character and discards an unfinished sequence
+func (f *filterWriter) Flush() error {
+	return f.write(f.d.Flush())
+}
+
+func (f *filterWriter) write(tokens []Token) error {
+	f.buf = f.buf[:0]
+	for _, tok := range tokens {
+		f.buf = f.render(f.buf, tok)
+	}
+	if len(f.buf) == 0 {
+		return nil
+	}
+	_, err := f.w.Write(f.buf)
+	return err
+}
+
+// StripWriter is an io.Writer that removes escape sequences and control
+// characters in the same way as StripANSI before passing the text on.
+// Sequences split across writes are handled; call Flush when the stream ends.
+type StripWriter struct {
+	filterWriter
+}
+
+// NewStripWriter creates a StripWriter that writes plain text to w
+func NewStripWriter(w io.Writer) *StripWriter {
+	return &StripWriter{filterWriter{w: w, render: appendPlainText}}
+}
@@ -0,0 +1,78 @@
+package terminal_go
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestStripANSI verifies that StripANSI removes sequences and controls but keeps text and whitespace
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain text", "hello\tworld\r\n", "hello\tworld\r\n"},
+		{"SGR", SetTextColor(1) + "red" + ResetAllAttributes + " " + BoldBright + "bold" + NormalIntensity, "red bold"},
+		{"Cursor movement", CursorPosition(5, 10) + "x" + EraseInLine + CursorUp(2), "x"},
+		{"DEC private modes", EnterAltScreen + HideCursor + "ui" + ShowCursor + ExitAltScreen, "ui"},
+		{"OSC 8 hyperlink with ST", "\033]8;;https://example.com/a;b\033\\link\033]8;;\033\\", "link"},
+		{"OSC 8 hyperlink with BEL", "\033]8;id=1;https://example.com\alink\033]8;;\a", "link"},
+		{"Window title", "\033]0;title\a" + "text", "text"},
+		{"Sixel DCS", "before\033Pq#0;2;0;0;0#0!10~-\033\\after", "beforeafter"},
+		{"APC", "\033_Gf=100;AAAA\033\\image", "image"},
+		{"PM and SOS", "\033^private\033\\a\033Xstring\033\\b", "ab"},
+		{"ESC sequences", SaveCursorPointerInMemory + "a" + DesignateCharacterSet(0, '0') + "b" + ReverseIndex, "ab"},
+		{"C0 controls", "a\a\bb\x00c\x7f", "abc"},
+		{"C1 controls", "a\u0085b\u009cc", "abc"},
+		{"UTF-8", SetTextColor(2) + "héllo wörld €" + ResetAllAttributes, "héllo wörld €"},
+		{"Invalid UTF-8", "a\xffb", "a�b"},
+		{"Cancelled sequence", "\033[31\x18ok", "ok"},
+	}
+
+	for _, tt := range tests {
+		if got := StripANSI(tt.input); got != tt.want {
+			t.Errorf("%s: StripANSI(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestStripWriter verifies that StripWriter strips sequences split across writes
+func TestStripWriter(t *testing.T) {
+	input := SetRGBTextColor(1, 2, 3) + "colored" + ResetAllAttributes + "\n" +
+		"\033]8;;https://example.com\033\\link\033]8;;\033\\ €\xe2"
+	want := "colored\nlink €�"
+
+	for size := 1; size <= len(input); size++ {
+		var buf bytes.Buffer
+		w := NewStripWriter(&buf)
+		for i := 0; i < len(input); i += size {
+			chunk := input[i:min(i+size, len(input))]
+			n, err := w.Write([]byte(chunk))
+			if err != nil || n != len(chunk) {
+				t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Flush() = %v", err)
+		}
+		if buf.String() != want {
+			t.Errorf("chunk size %d: got %q, want %q", size, buf.String(), want)
+		}
+	}
+}
+
+// ExampleStripANSI demonstrates removing colors from output before logging it
+func ExampleStripANSI() {
+	colored := SetTextColor(1) + "error:" + ResetAllAttributes + " disk full"
+	fmt.Println(StripANSI(colored))
+}
+
+// ExampleStripWriter demonstrates writing colored output to a plain text log
+func ExampleStripWriter() {
+	w := NewStripWriter(os.Stdout)
+	fmt.Fprint(w, BoldBright+"done"+NormalIntensity+"\n")
+	w.Flush()
+}
@@ -44,8 +44,16 @@ const (
 	// RestoreCursorPosition restores cursor to last saved position
 	RestoreCursorPosition = "\033[u"
 
-	// EnableVirtualTerminalProcessing enables VT processing
-	EnableVirtualTerminalProcessing = "\033[?1h"
+	// EnableApplicationCursorKeys (DECCKM) makes the terminal send the arrow keys as SS3 A to SS3 D
+	EnableApplicationCursorKeys = "\033[?1h"
+	// DisableApplicationCursorKeys (DECCKM) makes the terminal send the arrow keys as CSI A to CSI D
+	DisableApplicationCursorKeys = "\033[?1l"
+
+	// EnableVirtualTerminalProcessing enables application cursor keys.
+	//
+	// Deprecated: the sequence is DECCKM, not a switch for VT processing;
+	// use EnableApplicationCursorKeys.
+	EnableVirtualTerminalProcessing = EnableApplicationCursorKeys
 
 	// ResetAllAttributes resets all character attributes
 	ResetAllAttributes = "\033[0m"
@@ -96,6 +104,21 @@ const (
 
 	// ClearAndResetScrollback clears screen and scrollback buffer
 	ClearAndResetScrollback = "\033[3J"
+
+	// Select7BitControls (S7C1T) makes the terminal send 7-bit C1 controls such as ESC [ for CSI
+	Select7BitControls = "\033 F"
+	// Select8BitControls (S8C1T) makes the terminal send 8-bit C1 controls such as 0x9B for CSI
+	Select8BitControls = "\033 G"
+
+	// EnableBracketedPaste makes the terminal wrap pasted text in ESC [ 200 ~ and ESC [ 201 ~
+	EnableBracketedPaste = "\033[?2004h"
+	// DisableBracketedPaste sends pasted text as if it were typed
+	DisableBracketedPaste = "\033[?2004l"
+
+	// EnableFocusReporting makes the terminal send CSI I when it gains focus and CSI O when it loses it
+	EnableFocusReporting = "\033[?1004h"
+	// DisableFocusReporting stops focus reports
+	DisableFocusReporting = "\033[?1004l"
 )
 
 // CursorPosition sets the cursor position where subsequent text will begin
@@ -409,6 +409,8 @@ func TestConstants(t *testing.T) {
 		"ScrollDown":                      ScrollDown,
 		"SaveCursorPosition":              SaveCursorPosition,
 		"RestoreCursorPosition":           RestoreCursorPosition,
+		"EnableApplicationCursorKeys":     EnableApplicationCursorKeys,
+		"DisableApplicationCursorKeys":    DisableApplicationCursorKeys,
 		"EnableVirtualTerminalProcessing": EnableVirtualTerminalProcessing,
 		"ResetAllAttributes":              ResetAllAttributes,
 		"BoldBright":                      BoldBright,
@@ -425,6 +427,12 @@ func TestConstants(t *testing.T) {
 		"AutoWrap":                        AutoWrap,
 		"AutoWrapOff":                     AutoWrapOff,
 		"ClearAndResetScrollback":         ClearAndResetScrollback,
+		"Select7BitControls":              Select7BitControls,
+		"Select8BitControls":              Select8BitControls,
+		"EnableBracketedPaste":            EnableBracketedPaste,
+		"DisableBracketedPaste":           DisableBracketedPaste,
+		"EnableFocusReporting":            EnableFocusReporting,
+		"DisableFocusReporting":           DisableFocusReporting,
 	}
 
 	for name, constant := range constants {